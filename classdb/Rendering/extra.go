@@ -0,0 +1,50 @@
+package Rendering
+
+import "strconv"
+
+var uniformTypeNames = [UniformTypeMax]string{
+	UniformTypeSampler:                  "Sampler",
+	UniformTypeSamplerWithTexture:       "SamplerWithTexture",
+	UniformTypeTexture:                  "Texture",
+	UniformTypeImage:                    "Image",
+	UniformTypeTextureBuffer:            "TextureBuffer",
+	UniformTypeSamplerWithTextureBuffer: "SamplerWithTextureBuffer",
+	UniformTypeImageBuffer:              "ImageBuffer",
+	UniformTypeUniformBuffer:            "UniformBuffer",
+	UniformTypeStorageBuffer:            "StorageBuffer",
+	UniformTypeInputAttachment:          "InputAttachment",
+}
+
+// String returns the name of the uniform type, e.g. "StorageBuffer".
+func (t UniformType) String() string {
+	if t >= 0 && t < UniformTypeMax {
+		return uniformTypeNames[t]
+	}
+	return "UniformType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// IsBuffer reports whether uniforms of this type bind buffers.
+func (t UniformType) IsBuffer() bool {
+	switch t {
+	case UniformTypeTextureBuffer, UniformTypeSamplerWithTextureBuffer, UniformTypeImageBuffer,
+		UniformTypeUniformBuffer, UniformTypeStorageBuffer:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsWritable reports whether shaders can write to uniforms of this type.
+func (t UniformType) IsWritable() bool {
+	return t == UniformTypeImage || t == UniformTypeImageBuffer || t == UniformTypeStorageBuffer
+}
+
+// String returns the name of the feature, e.g. "SupportsBufferDeviceAddress".
+func (f Features) String() string {
+	switch f {
+	case SupportsBufferDeviceAddress:
+		return "SupportsBufferDeviceAddress"
+	default:
+		return "Features(" + strconv.Itoa(int(f)) + ")"
+	}
+}
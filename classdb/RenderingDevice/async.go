@@ -0,0 +1,169 @@
+package RenderingDevice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"graphics.gd/variant/RID"
+)
+
+// BufferGetDataAsyncTracked is like [Expanded.BufferGetDataAsync] but the request is tracked
+// until the callback is called, so that [Instance.DrainAsync] can wait for it.
+func (self Instance) BufferGetDataAsyncTracked(buffer RID.Buffer, callback func(data []byte), offset_bytes int, size_bytes int) error {
+	done := stateOf(self).async()
+	err := Expanded(self).BufferGetDataAsync(buffer, func(data []byte) {
+		defer done()
+		callback(data)
+	}, offset_bytes, size_bytes)
+	if err != nil {
+		done()
+	}
+	return err
+}
+
+// TextureGetDataAsyncTracked is like [Instance.TextureGetDataAsync] but the request is tracked
+// until the callback is called, so that [Instance.DrainAsync] can wait for it.
+func (self Instance) TextureGetDataAsyncTracked(texture RID.Texture, layer int, callback func(data []byte)) error {
+	done := stateOf(self).async()
+	err := self.TextureGetDataAsync(texture, layer, func(data []byte) {
+		defer done()
+		callback(data)
+	})
+	if err != nil {
+		done()
+	}
+	return err
+}
+
+// asyncSyncLimit is the number of times that a local device is submitted and synced while
+// waiting for async requests, before giving up. Requests normally complete within
+// [Instance.GetFrameDelay] + 1 syncs.
+const asyncSyncLimit = 16
+
+// async records an outstanding async request, the returned function must be called exactly
+// once, when the request completes.
+func (s *state) async() (done func()) {
+	s.mutex.Lock()
+	if s.pending == 0 {
+		s.drained = make(chan struct{})
+	}
+	s.pending++
+	s.mutex.Unlock()
+	return func() {
+		s.mutex.Lock()
+		s.pending--
+		if s.pending == 0 {
+			close(s.drained)
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// DrainAsync blocks until the callbacks of all tracked async requests (such as those made with
+// [Instance.BufferGetDataAsyncTracked] and [BufferReadSliceAsync]) have been called, or until
+// the context is canceled. Call this before freeing the device, so that no callbacks arrive
+// afterwards.
+//
+// Local devices are submitted and synced until the requests complete, or until an error is
+// returned because they are still pending after many syncs. The callbacks for the
+// main rendering device are only called as the engine draws frames, so DrainAsync must not be
+// called on the main thread when draining the main rendering device.
+func (self Instance) DrainAsync(ctx context.Context) error {
+	s := stateOf(self)
+	local := !isMainDevice(self)
+	for syncs := 0; ; syncs++ {
+		s.mutex.Lock()
+		pending, drained := s.pending, s.drained
+		s.mutex.Unlock()
+		if pending == 0 {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if local {
+			if syncs == asyncSyncLimit {
+				return fmt.Errorf("%d async requests still pending after %d syncs", pending, syncs)
+			}
+			self.SubmitTracked()
+			self.Sync()
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-drained:
+		}
+	}
+}
+
+// BufferGetDataContext is like [Expanded.BufferGetDataAsync] but waits for the data to arrive,
+// or for the context to be canceled. On cancellation (or when a local device still hasn't
+// delivered the data after many syncs), the request is left to complete in the background and its
+// data is discarded. See [Instance.DrainAsync] for the restrictions on waiting for the main
+// rendering device.
+func (self Instance) BufferGetDataContext(ctx context.Context, buffer RID.Buffer, offset_bytes, size_bytes int) ([]byte, error) {
+	result := make(chan []byte, 1)
+	err := self.BufferGetDataAsyncTracked(buffer, func(data []byte) {
+		result <- slices.Clone(data)
+	}, offset_bytes, size_bytes)
+	if err != nil {
+		return nil, err
+	}
+	return self.await(ctx, result)
+}
+
+// TextureGetDataContext is like [Instance.TextureGetDataAsync] but waits for the data to arrive,
+// or for the context to be canceled, see [Instance.BufferGetDataContext].
+func (self Instance) TextureGetDataContext(ctx context.Context, texture RID.Texture, layer int) ([]byte, error) {
+	result := make(chan []byte, 1)
+	err := self.TextureGetDataAsyncTracked(texture, layer, func(data []byte) {
+		result <- slices.Clone(data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return self.await(ctx, result)
+}
+
+// await waits for the result of an async request, submitting and syncing local devices until
+// it arrives, or until [asyncSyncLimit] syncs have been made.
+func (self Instance) await(ctx context.Context, result <-chan []byte) ([]byte, error) {
+	if isMainDevice(self) {
+		select {
+		case data := <-result:
+			return data, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	for syncs := 0; ; syncs++ {
+		select {
+		case data := <-result:
+			return data, nil
+		default:
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if syncs == asyncSyncLimit {
+			return nil, fmt.Errorf("async request still pending after %d syncs", syncs)
+		}
+		self.SubmitTracked()
+		self.Sync()
+	}
+}
+
+// MainDeviceComputeResult retrieves the entire contents of the buffer written by a compute list
+// on the main rendering device, calling done with the data once it is available. The main device
+// cannot be synced, as the engine submits its work once per frame, so the data only arrives after
+// the frame has been rendered and the GPU has finished with it, typically [Instance.GetFrameDelay]
+// frames later. Use a local device (see [NewLocalDevice]) to retrieve compute results immediately.
+func MainDeviceComputeResult(dev Instance, buffer RID.Buffer, done func([]byte)) error {
+	if !isMainDevice(dev) {
+		return errors.New("not the main rendering device, use Submit and Sync to wait for compute results")
+	}
+	return dev.BufferGetDataAsyncTracked(buffer, done, 0, 0)
+}
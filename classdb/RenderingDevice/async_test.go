@@ -0,0 +1,25 @@
+package RenderingDevice
+
+import "testing"
+
+func TestStateAsyncDrained(t *testing.T) {
+	var s state
+	first, second := s.async(), s.async()
+	drained := s.drained
+	first()
+	select {
+	case <-drained:
+		t.Fatal("drained with a request still pending")
+	default:
+	}
+	second()
+	select {
+	case <-drained:
+	default:
+		t.Fatal("not drained once every request completed")
+	}
+	s.async()
+	if s.drained == drained {
+		t.Error("drained channel was reused after being closed")
+	}
+}
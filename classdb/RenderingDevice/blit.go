@@ -0,0 +1,146 @@
+package RenderingDevice
+
+import (
+	"fmt"
+	"math"
+
+	"graphics.gd/classdb/RDSamplerState"
+	"graphics.gd/classdb/RDUniform"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Vector2i"
+)
+
+const blitGLSL = `#version 450
+layout(local_size_x = 8, local_size_y = 8, local_size_z = 1) in;
+layout(set = 0, binding = 0) uniform sampler2D source;
+layout(set = 0, binding = 1) uniform writeonly image2D target;
+layout(push_constant, std430) uniform Params { vec4 src; ivec4 dst; } params;
+void main() {
+	ivec2 texel = ivec2(gl_GlobalInvocationID.xy);
+	if (texel.x >= params.dst.z || texel.y >= params.dst.w) {
+		return;
+	}
+	vec2 uv = params.src.xy + (vec2(texel) + 0.5) / vec2(params.dst.zw) * params.src.zw;
+	imageStore(target, params.dst.xy + texel, textureLod(source, uv, 0.0));
+}
+`
+
+// TextureBlit copies the source region of the src texture onto the destination region of the dst
+// texture, scaling it to fit with the given filter, unlike [Instance.TextureCopy], which requires
+// both regions to be the same size. Regions are measured in texels, the destination region is
+// rounded to whole texels and must lie within the dst texture. The src texture must have been
+// created with [Rendering.TextureUsageSamplingBit] and the dst texture with
+// [Rendering.TextureUsageStorageBit].
+func TextureBlit(dev Instance, src RID.Texture, src_rect Rect2.PositionSize, dst RID.Texture, dst_rect Rect2.PositionSize, filter Rendering.SamplerFilter) error {
+	from, to := dev.TextureGetFormat(src), dev.TextureGetFormat(dst)
+	if from.UsageBits()&Rendering.TextureUsageSamplingBit == 0 {
+		return fmt.Errorf("texture %v cannot be sampled", src)
+	}
+	if to.UsageBits()&Rendering.TextureUsageStorageBit == 0 {
+		return fmt.Errorf("texture %v cannot be used as a storage image", dst)
+	}
+	x, y := int(math.Round(float64(dst_rect.Position.X))), int(math.Round(float64(dst_rect.Position.Y)))
+	w, h := int(math.Round(float64(dst_rect.Size.X))), int(math.Round(float64(dst_rect.Size.Y)))
+	if w <= 0 || h <= 0 || src_rect.Size.X <= 0 || src_rect.Size.Y <= 0 {
+		return nil
+	}
+	if x < 0 || y < 0 || x+w > to.Width() || y+h > to.Height() {
+		return fmt.Errorf("destination region %v is outside of the %vx%v texture", dst_rect, to.Width(), to.Height())
+	}
+	k, err := stateOf(dev).kernel(dev, "blit", blitGLSL)
+	if err != nil {
+		return err
+	}
+	state := RDSamplerState.New()
+	state.SetMagFilter(filter)
+	state.SetMinFilter(filter)
+	state.SetRepeatU(Rendering.SamplerRepeatModeClampToEdge)
+	state.SetRepeatV(Rendering.SamplerRepeatModeClampToEdge)
+	sampler := dev.SamplerCreate(state)
+	defer dev.FreeRidTracked(RID.Any(sampler))
+	set := dev.UniformSetCreate([]RDUniform.Instance{
+		uniform(Rendering.UniformTypeSamplerWithTexture, 0, RID.Any(sampler), RID.Any(src)),
+		uniform(Rendering.UniformTypeImage, 1, RID.Any(dst)),
+	}, k.shader, 0)
+	defer dev.FreeRidTracked(RID.Any(set))
+	width, height := float32(from.Width()), float32(from.Height())
+	push := pushConstant(
+		math.Float32bits(float32(src_rect.Position.X)/width), math.Float32bits(float32(src_rect.Position.Y)/height),
+		math.Float32bits(float32(src_rect.Size.X)/width), math.Float32bits(float32(src_rect.Size.Y)/height),
+		uint32(x), uint32(y), uint32(w), uint32(h),
+	)
+	list := dev.ComputeListBegin()
+	dev.ComputeListBindComputePipeline(list, k.pipeline)
+	dev.ComputeListBindUniformSet(list, set, 0)
+	dev.ComputeListSetPushConstant(list, push, len(push))
+	dev.ComputeListDispatch(list, groups(w, 8), groups(h, 8), 1)
+	dev.ComputeListEnd()
+	return nil
+}
+
+const downsampleGLSL = `#version 450
+layout(local_size_x = 8, local_size_y = 8, local_size_z = 1) in;
+layout(set = 0, binding = 0) uniform sampler2D source;
+layout(set = 0, binding = 1) uniform writeonly image2D target;
+layout(push_constant, std430) uniform Params { int factor; int pad0; int pad1; int pad2; } params;
+void main() {
+	ivec2 texel = ivec2(gl_GlobalInvocationID.xy);
+	if (any(greaterThanEqual(texel, imageSize(target)))) {
+		return;
+	}
+	ivec2 size = textureSize(source, 0);
+	ivec2 start = texel * params.factor;
+	ivec2 end = min(start + params.factor, size);
+	vec4 sum = vec4(0.0);
+	for (int y = start.y; y < end.y; y++) {
+		for (int x = start.x; x < end.x; x++) {
+			sum += texelFetch(source, ivec2(x, y), 0);
+		}
+	}
+	ivec2 count = end - start;
+	imageStore(target, texel, sum / float(count.x * count.y));
+}
+`
+
+// TextureDownsample creates a copy of the texture that is smaller by the given factor in each
+// dimension, with each texel being the average of the corresponding factor by factor block of the
+// source texels (a box filter), such as the half or quarter resolution inputs of bloom and depth of
+// field effects. Sizes that are not a multiple of the factor are rounded up, with the blocks at the
+// edges averaging the texels that remain. The src texture must have been created with
+// [Rendering.TextureUsageSamplingBit], and its format must support storage.
+func TextureDownsample(dev Instance, src RID.Texture, factor int) (RID.Texture, error) {
+	if factor < 1 {
+		return RID.Texture(0), fmt.Errorf("invalid downsample factor %d", factor)
+	}
+	format := dev.TextureGetFormat(src)
+	if format.UsageBits()&Rendering.TextureUsageSamplingBit == 0 {
+		return RID.Texture(0), fmt.Errorf("texture %v cannot be sampled", src)
+	}
+	size := Vector2i.New(groups(format.Width(), factor), groups(format.Height(), factor))
+	dst, err := TextureCreateComputeIO(dev, size, format.Format())
+	if err != nil {
+		return RID.Texture(0), err
+	}
+	k, err := stateOf(dev).kernel(dev, "downsample", downsampleGLSL)
+	if err != nil {
+		dev.FreeRidTracked(RID.Any(dst))
+		return RID.Texture(0), err
+	}
+	sampler := dev.SamplerCreate(RDSamplerState.New())
+	defer dev.FreeRidTracked(RID.Any(sampler))
+	set := dev.UniformSetCreate([]RDUniform.Instance{
+		uniform(Rendering.UniformTypeSamplerWithTexture, 0, RID.Any(sampler), RID.Any(src)),
+		uniform(Rendering.UniformTypeImage, 1, RID.Any(dst)),
+	}, k.shader, 0)
+	defer dev.FreeRidTracked(RID.Any(set))
+	push := pushConstant(uint32(factor))
+	list := dev.ComputeListBegin()
+	dev.ComputeListBindComputePipeline(list, k.pipeline)
+	dev.ComputeListBindUniformSet(list, set, 0)
+	dev.ComputeListSetPushConstant(list, push, len(push))
+	dev.ComputeListDispatch(list, groups(int(size.X), 8), groups(int(size.Y), 8), 1)
+	dev.ComputeListEnd()
+	return dst, nil
+}
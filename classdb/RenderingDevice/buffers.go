@@ -0,0 +1,228 @@
+package RenderingDevice
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"unsafe"
+
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+// BufferReadSlice reads count values of type T from the buffer, starting at the given byte
+// offset. T must not contain any pointers.
+//
+// Note: This function blocks the GPU until the data is retrieved, see [BufferReadSliceAsync].
+func BufferReadSlice[T any](dev Instance, buffer RID.Buffer, offset, count int) ([]T, error) {
+	size := count * int(unsafe.Sizeof([1]T{}[0]))
+	if size == 0 {
+		return nil, nil
+	}
+	return decodeSlice[T](Expanded(dev).BufferGetData(buffer, offset, size), count)
+}
+
+// BufferReadSliceAsync is the asynchronous version of [BufferReadSlice], done is called in a
+// certain amount of frames with the values the buffer had at the time of the request.
+func BufferReadSliceAsync[T any](dev Instance, buffer RID.Buffer, offset, count int, done func([]T, error)) error {
+	size := count * int(unsafe.Sizeof([1]T{}[0]))
+	if size == 0 {
+		done(nil, nil)
+		return nil
+	}
+	return dev.BufferGetDataAsyncTracked(buffer, func(data []byte) {
+		done(decodeSlice[T](data, count))
+	}, offset, size)
+}
+
+// decodeSlice reinterprets the given bytes as count values of type T.
+func decodeSlice[T any](data []byte, count int) ([]T, error) {
+	size := count * int(unsafe.Sizeof([1]T{}[0]))
+	if len(data) < size {
+		return nil, fmt.Errorf("read %d bytes, expected %d", len(data), size)
+	}
+	values := make([]T, count)
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(values))), size), data)
+	return values, nil
+}
+
+// StorageBufferCreateZeroed is like [Instance.StorageBufferCreate] but the contents of the buffer
+// are cleared to zero, rather than being left undefined. The size is rounded up to a multiple
+// of four bytes. If the buffer cannot be cleared (for example, because a compute list is active),
+// it is freed and an error is returned.
+func StorageBufferCreateZeroed(dev Instance, size_bytes int) (RID.StorageBuffer, error) {
+	size_bytes = (size_bytes + 3) &^ 3
+	buffer := dev.StorageBufferCreate(size_bytes)
+	if buffer == 0 {
+		return 0, fmt.Errorf("failed to create storage buffer of %d bytes", size_bytes)
+	}
+	if size_bytes > 0 {
+		if err := dev.BufferClear(RID.Buffer(buffer), 0, size_bytes); err != nil {
+			dev.FreeRidTracked(RID.Any(buffer))
+			return 0, fmt.Errorf("failed to clear storage buffer: %w", err)
+		}
+	}
+	stateOf(dev).sized(RID.Any(buffer), size_bytes)
+	return buffer, nil
+}
+
+// ErrBufferOverflow is returned when writing past the end of a buffer.
+var ErrBufferOverflow = errors.New("write exceeds the size of the buffer")
+
+// BufferWriter returns a writer that collects the data written to it, and uploads it to the buffer
+// at the given byte offset when closed, so that data can be serialized into the buffer with the
+// standard library's encoders (such as [encoding/binary.Write]). size is the size of the buffer in bytes,
+// writes beyond it fail with an error wrapping both [io.ErrShortWrite] and [ErrBufferOverflow].
+func BufferWriter(dev Instance, buffer RID.Buffer, offset, size int) io.WriteCloser {
+	return &bufferWriter{dev: dev, buffer: buffer, offset: offset, limit: max(size-offset, 0)}
+}
+
+type bufferWriter struct {
+	dev    Instance
+	buffer RID.Buffer
+	offset int
+	limit  int
+	data   []byte
+	closed bool
+}
+
+func (w *bufferWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("write to closed buffer writer")
+	}
+	n := min(len(p), w.limit-len(w.data))
+	w.data = append(w.data, p[:n]...)
+	if n < len(p) {
+		return n, fmt.Errorf("%w: %w", io.ErrShortWrite, ErrBufferOverflow)
+	}
+	return n, nil
+}
+
+// Close uploads the written data to the buffer.
+func (w *bufferWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if len(w.data) == 0 {
+		return nil
+	}
+	return w.dev.BufferUpdate(w.buffer, w.offset, len(w.data), w.data)
+}
+
+// AtomicCounterBuffer creates a storage buffer holding count uint32 counters, initialized to
+// zero, for compute shaders that count with atomicAdd, such as the number of visible instances
+// during GPU culling. The buffer may also be used as the arguments of an indirect dispatch.
+func AtomicCounterBuffer(dev Instance, count int) RID.StorageBuffer {
+	buffer := Expanded(dev).StorageBufferCreate(count*4, make([]byte, count*4), Rendering.StorageBufferUsageDispatchIndirect, 0)
+	stateOf(dev).sized(RID.Any(buffer), count*4)
+	return buffer
+}
+
+// ResetAtomicCounter clears the count counters of an [AtomicCounterBuffer] back to zero, which
+// must happen before each counting pass, otherwise the counts accumulate across passes.
+func ResetAtomicCounter(dev Instance, buffer RID.StorageBuffer, count int) error {
+	return dev.BufferClear(RID.Buffer(buffer), 0, count*4)
+}
+
+// BufferWriterAt returns a writer that uploads each write to the buffer at the given byte
+// offset, as a separate [Instance.BufferUpdate]. Writes beyond the end of the buffer are truncated
+// and fail with an error wrapping both [io.ErrShortWrite] and [ErrBufferOverflow], as with
+// [BufferWriter]. Errors reported by the device, such as when updating the buffer while a draw or
+// compute list is active, are returned wrapped.
+//
+// Warning: The size of the buffer is only known when it was created by one of the helpers in this
+// package (such as [StorageBufferCreateZeroed], [StorageBufferCreateOwned],
+// [Instance.VertexBufferCreateTracked] or [NewUniformRing]). Buffers created directly with
+// [Instance.StorageBufferCreate], [Instance.UniformBufferCreate] or any other method of the device
+// are NOT bounds checked, so writing past their end is left to the device to report.
+func (self Instance) BufferWriterAt(buffer RID.Buffer) io.WriterAt {
+	return bufferAt{dev: self, buffer: buffer}
+}
+
+// BufferReaderAt returns a reader that downloads the requested region of the buffer on each read.
+// When the size of the buffer is known (see [Instance.BufferWriterAt]), reads beyond the end of the
+// buffer are truncated and return [io.EOF].
+//
+// Note: Each read blocks the GPU until the data is retrieved.
+func (self Instance) BufferReaderAt(buffer RID.Buffer) io.ReaderAt {
+	return bufferAt{dev: self, buffer: buffer}
+}
+
+type bufferAt struct {
+	dev    Instance
+	buffer RID.Buffer
+}
+
+// limit returns the number of bytes of the buffer available from offset, or n if the size of the
+// buffer is unknown.
+func (b bufferAt) limit(offset int64, n int) int {
+	s := stateOf(b.dev)
+	s.mutex.Lock()
+	size, ok := s.sizes[RID.Any(b.buffer)]
+	s.mutex.Unlock()
+	if !ok {
+		return n
+	}
+	return int(max(min(int64(n), int64(size)-offset), 0))
+}
+
+func (b bufferAt) WriteAt(p []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, fmt.Errorf("negative offset %d", offset)
+	}
+	n := b.limit(offset, len(p))
+	if n > 0 {
+		if err := b.dev.BufferUpdate(b.buffer, int(offset), n, p[:n]); err != nil {
+			return 0, fmt.Errorf("updating %d bytes of buffer %v at offset %d: %w", n, b.buffer, offset, err)
+		}
+	}
+	if n < len(p) {
+		return n, fmt.Errorf("%w: %w", io.ErrShortWrite, ErrBufferOverflow)
+	}
+	return n, nil
+}
+
+func (b bufferAt) ReadAt(p []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, fmt.Errorf("negative offset %d", offset)
+	}
+	n := b.limit(offset, len(p))
+	if n > 0 {
+		data := Expanded(b.dev).BufferGetData(b.buffer, int(offset), n)
+		if len(data) < n {
+			return copy(p, data), fmt.Errorf("read %d bytes of buffer %v at offset %d, expected %d", len(data), b.buffer, offset, n)
+		}
+		copy(p, data)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// StorageBufferForShaderBinding creates a zeroed storage buffer for the storage buffer block that
+// the shader (which must have been created with [ShaderCreate]) declares at the given set and
+// binding, large enough to hold count elements of the runtime sized array that ends the block,
+// as per the layout reflected from the shader. This ensures that the stride of the elements
+// matches the shader's declaration of them.
+func StorageBufferForShaderBinding(dev Instance, shader RID.Shader, set, binding, count int) (RID.StorageBuffer, error) {
+	info, ok := dev.ShaderGetReflection(shader)
+	if !ok {
+		return RID.StorageBuffer(0), fmt.Errorf("shader %v has no reflection information", shader)
+	}
+	i, found := slices.BinarySearchFunc(info.Uniforms, ShaderUniform{Set: set, Binding: binding}, compareUniforms)
+	if !found {
+		return RID.StorageBuffer(0), fmt.Errorf("shader %v has no uniform at set %d, binding %d", shader, set, binding)
+	}
+	uniform := info.Uniforms[i]
+	if uniform.Type != Rendering.UniformTypeStorageBuffer {
+		return RID.StorageBuffer(0), fmt.Errorf("uniform at set %d, binding %d of shader %v is a %v, not a storage buffer", set, binding, shader, uniform.Type)
+	}
+	if uniform.Stride == 0 {
+		return RID.StorageBuffer(0), fmt.Errorf("storage buffer at set %d, binding %d of shader %v does not end with a runtime sized array", set, binding, shader)
+	}
+	size := uniform.Size + count*uniform.Stride
+	return StorageBufferCreateZeroed(dev, size)
+}
@@ -1090,6 +1090,9 @@ func (self Instance) ComputeListEnd() { //gd:RenderingDevice.compute_list_end
 Tries to free an object in the RenderingDevice. To avoid memory leaks, this should be called after using an object as memory management does not occur automatically when using RenderingDevice directly.
 */
 func (self Instance) FreeRid(rid RID.Any) { //gd:RenderingDevice.free_rid
+	if !self.freeing(rid) {
+		return
+	}
 	Advanced(self).FreeRid(RID.Any(rid))
 }
 
@@ -0,0 +1,195 @@
+package RenderingDevice
+
+import (
+	"fmt"
+	"strings"
+
+	"graphics.gd/variant/RID"
+)
+
+// Command is a single call recorded by a [CommandLog].
+type Command struct {
+	Name string // name of the [Instance] method.
+	Args []any
+}
+
+// String returns the command formatted as a call, such as "DrawListDraw(1, false, 1)".
+func (cmd Command) String() string {
+	var args = make([]string, len(cmd.Args))
+	for i, arg := range cmd.Args {
+		if data, ok := arg.([]byte); ok {
+			args[i] = fmt.Sprintf("%x", data)
+			continue
+		}
+		args[i] = fmt.Sprint(arg)
+	}
+	return cmd.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// CommandLog records the draw list and compute list calls made through it, so that tests can
+// compare the sequence of commands issued by rendering code against a golden snapshot. A log
+// created with [NewCommandLog] forwards each call to the device, whereas one created with
+// [NewCommandRecorder] only records them, so that it can be used without a GPU present.
+type CommandLog struct {
+	Commands []Command
+
+	dev     Instance
+	forward bool
+	lists   int
+}
+
+// NewCommandLog returns a [CommandLog] that records each call before making it on the device.
+func NewCommandLog(dev Instance) *CommandLog {
+	return &CommandLog{dev: dev, forward: true}
+}
+
+// NewCommandRecorder returns a [CommandLog] that records each call without making it, draw and
+// compute lists are numbered sequentially from 1.
+func NewCommandRecorder() *CommandLog {
+	return new(CommandLog)
+}
+
+// String returns each of the recorded commands on a separate line.
+func (log *CommandLog) String() string {
+	var buf strings.Builder
+	for _, cmd := range log.Commands {
+		buf.WriteString(cmd.String())
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// Reset discards the recorded commands.
+func (log *CommandLog) Reset() {
+	log.Commands = log.Commands[:0]
+}
+
+func (log *CommandLog) record(name string, args ...any) {
+	log.Commands = append(log.Commands, Command{Name: name, Args: args})
+}
+
+// list returns the draw or compute list returned by begin, or the next sequential list when the
+// log only records.
+func (log *CommandLog) list(begin func() int) int {
+	if log.forward {
+		return begin()
+	}
+	log.lists++
+	return log.lists
+}
+
+// DrawListBegin records and makes an [Instance.DrawListBegin] call.
+func (log *CommandLog) DrawListBegin(framebuffer RID.Framebuffer) int {
+	log.record("DrawListBegin", framebuffer)
+	return log.list(func() int { return log.dev.DrawListBegin(framebuffer) })
+}
+
+// DrawListBindRenderPipeline records and makes an [Instance.DrawListBindRenderPipeline] call.
+func (log *CommandLog) DrawListBindRenderPipeline(draw_list int, render_pipeline RID.RenderPipeline) {
+	log.record("DrawListBindRenderPipeline", draw_list, render_pipeline)
+	if log.forward {
+		log.dev.DrawListBindRenderPipeline(draw_list, render_pipeline)
+	}
+}
+
+// DrawListBindUniformSet records and makes an [Instance.DrawListBindUniformSet] call.
+func (log *CommandLog) DrawListBindUniformSet(draw_list int, uniform_set RID.UniformSet, set_index int) {
+	log.record("DrawListBindUniformSet", draw_list, uniform_set, set_index)
+	if log.forward {
+		log.dev.DrawListBindUniformSet(draw_list, uniform_set, set_index)
+	}
+}
+
+// DrawListBindVertexArray records and makes an [Instance.DrawListBindVertexArray] call.
+func (log *CommandLog) DrawListBindVertexArray(draw_list int, vertex_array RID.VertexArray) {
+	log.record("DrawListBindVertexArray", draw_list, vertex_array)
+	if log.forward {
+		log.dev.DrawListBindVertexArray(draw_list, vertex_array)
+	}
+}
+
+// DrawListBindIndexArray records and makes an [Instance.DrawListBindIndexArray] call.
+func (log *CommandLog) DrawListBindIndexArray(draw_list int, index_array RID.IndexArray) {
+	log.record("DrawListBindIndexArray", draw_list, index_array)
+	if log.forward {
+		log.dev.DrawListBindIndexArray(draw_list, index_array)
+	}
+}
+
+// DrawListSetPushConstant records and makes an [Instance.DrawListSetPushConstant] call.
+func (log *CommandLog) DrawListSetPushConstant(draw_list int, buffer []byte, size_bytes int) {
+	log.record("DrawListSetPushConstant", draw_list, append([]byte(nil), buffer...), size_bytes)
+	if log.forward {
+		log.dev.DrawListSetPushConstant(draw_list, buffer, size_bytes)
+	}
+}
+
+// DrawListDraw records and makes an [Instance.DrawListDraw] call.
+func (log *CommandLog) DrawListDraw(draw_list int, use_indices bool, instances int) {
+	log.record("DrawListDraw", draw_list, use_indices, instances)
+	if log.forward {
+		log.dev.DrawListDraw(draw_list, use_indices, instances)
+	}
+}
+
+// DrawListEnd records and makes an [Instance.DrawListEnd] call.
+func (log *CommandLog) DrawListEnd() {
+	log.record("DrawListEnd")
+	if log.forward {
+		log.dev.DrawListEnd()
+	}
+}
+
+// ComputeListBegin records and makes an [Instance.ComputeListBegin] call.
+func (log *CommandLog) ComputeListBegin() int {
+	log.record("ComputeListBegin")
+	return log.list(log.dev.ComputeListBegin)
+}
+
+// ComputeListBindComputePipeline records and makes an [Instance.ComputeListBindComputePipeline] call.
+func (log *CommandLog) ComputeListBindComputePipeline(compute_list int, compute_pipeline RID.ComputePipeline) {
+	log.record("ComputeListBindComputePipeline", compute_list, compute_pipeline)
+	if log.forward {
+		log.dev.ComputeListBindComputePipeline(compute_list, compute_pipeline)
+	}
+}
+
+// ComputeListBindUniformSet records and makes an [Instance.ComputeListBindUniformSet] call.
+func (log *CommandLog) ComputeListBindUniformSet(compute_list int, uniform_set RID.UniformSet, set_index int) {
+	log.record("ComputeListBindUniformSet", compute_list, uniform_set, set_index)
+	if log.forward {
+		log.dev.ComputeListBindUniformSet(compute_list, uniform_set, set_index)
+	}
+}
+
+// ComputeListSetPushConstant records and makes an [Instance.ComputeListSetPushConstant] call.
+func (log *CommandLog) ComputeListSetPushConstant(compute_list int, buffer []byte, size_bytes int) {
+	log.record("ComputeListSetPushConstant", compute_list, append([]byte(nil), buffer...), size_bytes)
+	if log.forward {
+		log.dev.ComputeListSetPushConstant(compute_list, buffer, size_bytes)
+	}
+}
+
+// ComputeListDispatch records and makes an [Instance.ComputeListDispatch] call.
+func (log *CommandLog) ComputeListDispatch(compute_list int, x_groups, y_groups, z_groups int) {
+	log.record("ComputeListDispatch", compute_list, x_groups, y_groups, z_groups)
+	if log.forward {
+		log.dev.ComputeListDispatch(compute_list, x_groups, y_groups, z_groups)
+	}
+}
+
+// ComputeListAddBarrier records and makes an [Instance.ComputeListAddBarrier] call.
+func (log *CommandLog) ComputeListAddBarrier(compute_list int) {
+	log.record("ComputeListAddBarrier", compute_list)
+	if log.forward {
+		log.dev.ComputeListAddBarrier(compute_list)
+	}
+}
+
+// ComputeListEnd records and makes an [Instance.ComputeListEnd] call.
+func (log *CommandLog) ComputeListEnd() {
+	log.record("ComputeListEnd")
+	if log.forward {
+		log.dev.ComputeListEnd()
+	}
+}
@@ -0,0 +1,49 @@
+package RenderingDevice
+
+import (
+	"testing"
+
+	"graphics.gd/variant/RID"
+)
+
+func TestCommandRecorder(t *testing.T) {
+	log := NewCommandRecorder()
+	draw := log.DrawListBegin(RID.Framebuffer(1))
+	log.DrawListBindRenderPipeline(draw, RID.RenderPipeline(2))
+	log.DrawListBindUniformSet(draw, RID.UniformSet(3), 0)
+	log.DrawListBindVertexArray(draw, RID.VertexArray(4))
+	log.DrawListBindIndexArray(draw, RID.IndexArray(5))
+	log.DrawListSetPushConstant(draw, []byte{1, 0, 0, 0, 0xff, 0, 0, 0}, 8)
+	log.DrawListDraw(draw, true, 2)
+	log.DrawListEnd()
+	compute := log.ComputeListBegin()
+	log.ComputeListBindComputePipeline(compute, RID.ComputePipeline(6))
+	log.ComputeListBindUniformSet(compute, RID.UniformSet(7), 1)
+	log.ComputeListDispatch(compute, 8, 4, 1)
+	log.ComputeListAddBarrier(compute)
+	log.ComputeListDispatch(compute, 1, 1, 1)
+	log.ComputeListEnd()
+	const golden = `DrawListBegin(1)
+DrawListBindRenderPipeline(1, 2)
+DrawListBindUniformSet(1, 3, 0)
+DrawListBindVertexArray(1, 4)
+DrawListBindIndexArray(1, 5)
+DrawListSetPushConstant(1, 01000000ff000000, 8)
+DrawListDraw(1, true, 2)
+DrawListEnd()
+ComputeListBegin()
+ComputeListBindComputePipeline(2, 6)
+ComputeListBindUniformSet(2, 7, 1)
+ComputeListDispatch(2, 8, 4, 1)
+ComputeListAddBarrier(2)
+ComputeListDispatch(2, 1, 1, 1)
+ComputeListEnd()
+`
+	if got := log.String(); got != golden {
+		t.Errorf("recorded commands:\n%s\nwant:\n%s", got, golden)
+	}
+	log.Reset()
+	if got := log.String(); got != "" {
+		t.Errorf("recorded commands after Reset:\n%s", got)
+	}
+}
@@ -0,0 +1,243 @@
+package RenderingDevice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"graphics.gd/classdb/RDShaderSPIRV"
+	"graphics.gd/classdb/RDUniform"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+// ComputeListDispatchChecked is like [Instance.ComputeListDispatch], except that the dispatch
+// is first validated against the device's limits, using the local workgroup size of the shader
+// (which must have been created with [ShaderCreate]). An error is returned, without dispatching,
+// if the workgroup size exceeds [Rendering.LimitMaxComputeWorkgroupInvocations] or the per-axis
+// size limits, or if the group counts exceed the per-axis workgroup count limits. Such dispatches
+// are undefined behaviour and may appear to work on one GPU while producing garbage on another.
+func (self Instance) ComputeListDispatchChecked(compute_list int, shader RID.Shader, x_groups, y_groups, z_groups int) error {
+	info, ok := stateOf(self).reflect(shader)
+	if !ok || info.LocalSize == [3]int{} {
+		return fmt.Errorf("local workgroup size of shader %v is unknown", shader)
+	}
+	local := info.LocalSize
+	if invocations, limit := local[0]*local[1]*local[2], self.LimitGet(Rendering.LimitMaxComputeWorkgroupInvocations); invocations > limit {
+		return fmt.Errorf("workgroup of %dx%dx%d has %d invocations, exceeding the device limit of %d", local[0], local[1], local[2], invocations, limit)
+	}
+	counts := [3]int{x_groups, y_groups, z_groups}
+	sizeLimits := [3]Rendering.Limit{Rendering.LimitMaxComputeWorkgroupSizeX, Rendering.LimitMaxComputeWorkgroupSizeY, Rendering.LimitMaxComputeWorkgroupSizeZ}
+	countLimits := [3]Rendering.Limit{Rendering.LimitMaxComputeWorkgroupCountX, Rendering.LimitMaxComputeWorkgroupCountY, Rendering.LimitMaxComputeWorkgroupCountZ}
+	for axis, name := range [3]string{"x", "y", "z"} {
+		if limit := self.LimitGet(sizeLimits[axis]); local[axis] > limit {
+			return fmt.Errorf("workgroup size %d on the %s axis exceeds the device limit of %d", local[axis], name, limit)
+		}
+		if limit := self.LimitGet(countLimits[axis]); counts[axis] > limit {
+			return fmt.Errorf("%d workgroups on the %s axis exceeds the device limit of %d", counts[axis], name, limit)
+		}
+	}
+	self.ComputeListDispatch(compute_list, x_groups, y_groups, z_groups)
+	return nil
+}
+
+// ComputeListDispatchThreads dispatches enough workgroups to cover x by y by z invocations (threads),
+// rounding up the number of workgroups on each axis as per the local workgroup size of the shader,
+// which must have been created with [ShaderCreate]. The shader must check whether its invocation
+// is within bounds, as the last workgroup on each axis may extend beyond them. The dispatch is
+// validated as per [Instance.ComputeListDispatchChecked].
+func (self Instance) ComputeListDispatchThreads(compute_list int, shader RID.Shader, x, y, z int) error {
+	info, ok := stateOf(self).reflect(shader)
+	if !ok || info.LocalSize == [3]int{} {
+		return fmt.Errorf("local workgroup size of shader %v is unknown", shader)
+	}
+	local := info.LocalSize
+	return self.ComputeListDispatchChecked(compute_list, shader, groups(x, local[0]), groups(y, local[1]), groups(z, local[2]))
+}
+
+// RunComputeSource compiles the GLSL compute shader source, binds sets[i] as uniform set i and
+// the push constant (padded to a multiple of 16 bytes), then dispatches the given number of
+// workgroups. The uniform sets are freed afterwards, whereas the shader and pipeline are cached
+// on the device, so repeated calls with the same source only compile it once.
+func RunComputeSource(dev Instance, glsl string, sets [][]RDUniform.Instance, push []byte, x_groups, y_groups, z_groups int) error {
+	k, err := stateOf(dev).kernel(dev, "compute", glsl)
+	if err != nil {
+		return err
+	}
+	var uniform_sets = make([]RID.UniformSet, len(sets))
+	for i, uniforms := range sets {
+		if len(uniforms) == 0 {
+			continue
+		}
+		uniform_sets[i] = dev.UniformSetCreate(uniforms, k.shader, i)
+		if uniform_sets[i] == 0 {
+			return fmt.Errorf("failed to create uniform set %d", i)
+		}
+		defer dev.FreeRidTracked(RID.Any(uniform_sets[i]))
+	}
+	list := dev.ComputeListBegin()
+	defer dev.ComputeListEnd()
+	dev.ComputeListBindComputePipeline(list, k.pipeline)
+	for i, set := range uniform_sets {
+		if set != 0 {
+			dev.ComputeListBindUniformSet(list, set, i)
+		}
+	}
+	if len(push) > 0 {
+		padded := make([]byte, (len(push)+15)&^15)
+		copy(padded, push)
+		dev.ComputeListSetPushConstant(list, padded, len(padded))
+	}
+	return dev.ComputeListDispatchChecked(list, k.shader, x_groups, y_groups, z_groups)
+}
+
+// BenchmarkDispatch measures the average GPU time of dispatching the compute pipeline, with sets[i]
+// bound as uniform set i and the given push constant, by submitting and syncing the dispatch the
+// given number of times, with timestamps captured around it. The device must be a local device,
+// as the main rendering device is submitted by the engine.
+func BenchmarkDispatch(dev Instance, pipeline RID.ComputePipeline, sets []RID.UniformSet, push []byte, x_groups, y_groups, z_groups int, iterations int) (time.Duration, error) {
+	if iterations <= 0 {
+		return 0, fmt.Errorf("invalid number of iterations %d", iterations)
+	}
+	if isMainDevice(dev) {
+		return 0, errors.New("dispatches can only be benchmarked on a local device")
+	}
+	const begin, end = "BenchmarkDispatch begin", "BenchmarkDispatch end"
+	var total time.Duration
+	for range iterations {
+		dev.CaptureTimestamp(begin)
+		list := dev.ComputeListBegin()
+		dev.ComputeListBindComputePipeline(list, pipeline)
+		for i, set := range sets {
+			dev.ComputeListBindUniformSet(list, set, i)
+		}
+		if len(push) > 0 {
+			dev.ComputeListSetPushConstant(list, push, len(push))
+		}
+		dev.ComputeListDispatch(list, x_groups, y_groups, z_groups)
+		dev.ComputeListEnd()
+		dev.CaptureTimestamp(end)
+		dev.SubmitTracked()
+		dev.Sync()
+		var from, to time.Duration = -1, -1
+		for _, ts := range Timestamps(dev) {
+			switch ts.Name {
+			case begin:
+				from = ts.GPU
+			case end:
+				to = ts.GPU
+			}
+		}
+		if from < 0 || to < 0 {
+			return 0, errors.New("dispatch timestamps were not captured")
+		}
+		total += to - from
+	}
+	return total / time.Duration(iterations), nil
+}
+
+// WithComputeBarrier records the commands of before into the compute list, followed by a barrier
+// and then the commands of after, so that the commands recorded by after see the results of those
+// recorded by before, such as when one dispatch reads what the previous one wrote.
+func (self Instance) WithComputeBarrier(compute_list int, before, after func()) {
+	before()
+	self.ComputeListAddBarrier(compute_list)
+	after()
+}
+
+// ComputePipelineCreateChecked is like [Instance.ComputePipelineCreate], except that the device is
+// first checked for each of the required features, so that a shader relying on features that the
+// hardware lacks fails here with a clear error, rather than producing garbage when dispatched. An
+// error listing the missing features is returned, without creating the pipeline.
+func ComputePipelineCreateChecked(dev Instance, shader RID.Shader, required ...Rendering.Features) (RID.ComputePipeline, error) {
+	if _, ok := stateOf(dev).reflect(shader); ok && !dev.ShaderIsCompute(shader) {
+		return RID.ComputePipeline(0), fmt.Errorf("shader %v is not a compute shader", shader)
+	}
+	var missing []string
+	for _, feature := range required {
+		if !dev.HasFeature(feature) {
+			missing = append(missing, feature.String())
+		}
+	}
+	if len(missing) > 0 {
+		return RID.ComputePipeline(0), fmt.Errorf("device does not support required features: %s", strings.Join(missing, ", "))
+	}
+	pipeline := dev.ComputePipelineCreate(shader)
+	if !dev.ComputePipelineIsValid(pipeline) {
+		return RID.ComputePipeline(0), fmt.Errorf("failed to create compute pipeline for shader %v", shader)
+	}
+	return pipeline, nil
+}
+
+// UniformSpec describes a storage buffer for [RunCompute].
+type UniformSpec struct {
+	Set     int
+	Binding int
+	Data    []byte // initial contents of the buffer, the rest is zeroed.
+	Size    int    // size of the buffer in bytes, if larger than Data.
+	Output  bool   // whether to return the contents of the buffer after the dispatch.
+}
+
+// RunCompute runs the SPIR-V compute shader once on a new local device (see [NewLocalDevice]),
+// binding each of the uniforms as a storage buffer, dispatching the given number of workgroups and
+// waiting for the GPU to finish. The resulting contents of each of the buffers marked as an Output
+// are returned, in the same order as the uniforms (with nil for the other buffers). The device and
+// every resource created on it are freed before returning.
+func RunCompute(spirv []byte, uniforms []UniformSpec, groups [3]int) ([][]byte, error) {
+	dev, err := NewLocalDevice()
+	if err != nil {
+		return nil, err
+	}
+	defer FreeLocalDevice(dev)
+	code := RDShaderSPIRV.New()
+	code.SetBytecodeCompute(spirv)
+	shader, err := ShaderCreate(dev, code, "compute")
+	if err != nil {
+		return nil, err
+	}
+	defer dev.FreeRidTracked(RID.Any(shader))
+	pipeline, err := ComputePipelineCreateChecked(dev, shader)
+	if err != nil {
+		return nil, err
+	}
+	defer dev.FreeRidTracked(RID.Any(pipeline))
+	buffers := make([]RID.StorageBuffer, len(uniforms))
+	sets := make(map[int][]RDUniform.Instance)
+	for i, spec := range uniforms {
+		size := max(spec.Size, len(spec.Data))
+		data := make([]byte, size)
+		copy(data, spec.Data)
+		buffers[i] = Expanded(dev).StorageBufferCreate(size, data, 0, 0)
+		if buffers[i] == RID.StorageBuffer(0) {
+			return nil, fmt.Errorf("failed to create storage buffer of %d bytes for binding %d", size, spec.Binding)
+		}
+		defer dev.FreeRidTracked(RID.Any(buffers[i]))
+		sets[spec.Set] = append(sets[spec.Set], uniform(Rendering.UniformTypeStorageBuffer, spec.Binding, RID.Any(buffers[i])))
+	}
+	list := dev.ComputeListBegin()
+	dev.ComputeListBindComputePipeline(list, pipeline)
+	for index, set := range sets {
+		rid := dev.UniformSetCreate(set, shader, index)
+		if rid == RID.UniformSet(0) {
+			dev.ComputeListEnd()
+			return nil, fmt.Errorf("failed to create uniform set %d", index)
+		}
+		defer dev.FreeRidTracked(RID.Any(rid))
+		dev.ComputeListBindUniformSet(list, rid, index)
+	}
+	if err := dev.ComputeListDispatchChecked(list, shader, groups[0], groups[1], groups[2]); err != nil {
+		dev.ComputeListEnd()
+		return nil, err
+	}
+	dev.ComputeListEnd()
+	dev.SubmitTracked()
+	dev.Sync()
+	outputs := make([][]byte, len(uniforms))
+	for i, spec := range uniforms {
+		if spec.Output {
+			outputs[i] = dev.BufferGetData(RID.Buffer(buffers[i]))
+		}
+	}
+	return outputs, nil
+}
@@ -0,0 +1,108 @@
+package RenderingDevice
+
+import (
+	"fmt"
+	"strings"
+
+	"graphics.gd/classdb/Rendering"
+)
+
+var deviceTypeNames = [Rendering.DeviceTypeMax]string{
+	Rendering.DeviceTypeOther:         "other",
+	Rendering.DeviceTypeIntegratedGpu: "integrated GPU",
+	Rendering.DeviceTypeDiscreteGpu:   "discrete GPU",
+	Rendering.DeviceTypeVirtualGpu:    "virtual GPU",
+	Rendering.DeviceTypeCpu:           "CPU",
+}
+
+var limitNames = []struct {
+	name  string
+	limit Rendering.Limit
+}{
+	{"max bound uniform sets", Rendering.LimitMaxBoundUniformSets},
+	{"max framebuffer color attachments", Rendering.LimitMaxFramebufferColorAttachments},
+	{"max textures per uniform set", Rendering.LimitMaxTexturesPerUniformSet},
+	{"max samplers per uniform set", Rendering.LimitMaxSamplersPerUniformSet},
+	{"max storage buffers per uniform set", Rendering.LimitMaxStorageBuffersPerUniformSet},
+	{"max storage images per uniform set", Rendering.LimitMaxStorageImagesPerUniformSet},
+	{"max uniform buffers per uniform set", Rendering.LimitMaxUniformBuffersPerUniformSet},
+	{"max draw indexed index", Rendering.LimitMaxDrawIndexedIndex},
+	{"max framebuffer height", Rendering.LimitMaxFramebufferHeight},
+	{"max framebuffer width", Rendering.LimitMaxFramebufferWidth},
+	{"max texture array layers", Rendering.LimitMaxTextureArrayLayers},
+	{"max texture size 1d", Rendering.LimitMaxTextureSize1d},
+	{"max texture size 2d", Rendering.LimitMaxTextureSize2d},
+	{"max texture size 3d", Rendering.LimitMaxTextureSize3d},
+	{"max texture size cube", Rendering.LimitMaxTextureSizeCube},
+	{"max textures per shader stage", Rendering.LimitMaxTexturesPerShaderStage},
+	{"max samplers per shader stage", Rendering.LimitMaxSamplersPerShaderStage},
+	{"max storage buffers per shader stage", Rendering.LimitMaxStorageBuffersPerShaderStage},
+	{"max storage images per shader stage", Rendering.LimitMaxStorageImagesPerShaderStage},
+	{"max uniform buffers per shader stage", Rendering.LimitMaxUniformBuffersPerShaderStage},
+	{"max push constant size", Rendering.LimitMaxPushConstantSize},
+	{"max uniform buffer size", Rendering.LimitMaxUniformBufferSize},
+	{"max vertex input attribute offset", Rendering.LimitMaxVertexInputAttributeOffset},
+	{"max vertex input attributes", Rendering.LimitMaxVertexInputAttributes},
+	{"max vertex input bindings", Rendering.LimitMaxVertexInputBindings},
+	{"max vertex input binding stride", Rendering.LimitMaxVertexInputBindingStride},
+	{"min uniform buffer offset alignment", Rendering.LimitMinUniformBufferOffsetAlignment},
+	{"max compute shared memory size", Rendering.LimitMaxComputeSharedMemorySize},
+	{"max compute workgroup count x", Rendering.LimitMaxComputeWorkgroupCountX},
+	{"max compute workgroup count y", Rendering.LimitMaxComputeWorkgroupCountY},
+	{"max compute workgroup count z", Rendering.LimitMaxComputeWorkgroupCountZ},
+	{"max compute workgroup invocations", Rendering.LimitMaxComputeWorkgroupInvocations},
+	{"max compute workgroup size x", Rendering.LimitMaxComputeWorkgroupSizeX},
+	{"max compute workgroup size y", Rendering.LimitMaxComputeWorkgroupSizeY},
+	{"max compute workgroup size z", Rendering.LimitMaxComputeWorkgroupSizeZ},
+	{"max viewport dimensions x", Rendering.LimitMaxViewportDimensionsX},
+	{"max viewport dimensions y", Rendering.LimitMaxViewportDimensionsY},
+	{"subgroup size", limitSubgroupSize},
+	{"subgroup min size", limitSubgroupMinSize},
+	{"subgroup max size", limitSubgroupMaxSize},
+}
+
+// DiagnosticReport returns a human-readable, multi-line report of the device, its limits, features
+// and memory usage, suitable for including in bug reports. Memory details are omitted when the
+// engine was built without memory tracking.
+func (self Instance) DiagnosticReport() string {
+	var report strings.Builder
+	fmt.Fprintf(&report, "Device: %s\n", self.GetDeviceName())
+	fmt.Fprintf(&report, "Vendor: %s\n", self.GetDeviceVendorName())
+	if kind := videoAdapterType(); kind >= 0 && kind < Rendering.DeviceTypeMax {
+		fmt.Fprintf(&report, "Adapter type: %s\n", deviceTypeNames[kind])
+	}
+	fmt.Fprintf(&report, "Main device: %v\n", isMainDevice(self))
+	fmt.Fprintf(&report, "Software: %v\n", self.IsSoftwareDevice())
+	fmt.Fprintf(&report, "Pipeline cache UUID: %s\n", self.GetDevicePipelineCacheUuid())
+	report.WriteString("\nLimits:\n")
+	for _, limit := range limitNames {
+		fmt.Fprintf(&report, "  %s: %d\n", limit.name, self.LimitGet(limit.limit))
+	}
+	report.WriteString("\nFeatures:\n")
+	fmt.Fprintf(&report, "  buffer device address: %v\n", self.HasFeature(Rendering.SupportsBufferDeviceAddress))
+	fmt.Fprintf(&report, "  subgroup operations: %v\n", self.SupportsSubgroupOps())
+	report.WriteString("\nMemory:\n")
+	fmt.Fprintf(&report, "  textures: %d bytes\n", self.GetMemoryUsage(Rendering.MemoryTextures))
+	fmt.Fprintf(&report, "  buffers: %d bytes\n", self.GetMemoryUsage(Rendering.MemoryBuffers))
+	fmt.Fprintf(&report, "  total: %d bytes\n", self.GetMemoryUsage(Rendering.MemoryTotal))
+	if total := self.GetDeviceTotalMemory(); total > 0 {
+		fmt.Fprintf(&report, "  device total: %d bytes\n", total)
+		fmt.Fprintf(&report, "  device allocations: %d\n", self.GetDeviceAllocationCount())
+		fmt.Fprintf(&report, "\n%s\n", strings.TrimSpace(self.GetDriverAndDeviceMemoryReport()))
+	}
+	return report.String()
+}
+
+// MemoryPressure returns the memory that the device has allocated for textures and buffers, as a
+// fraction of the given budget in bytes, clamped to the range [0, 1]. The engine cannot query the
+// amount of video memory available, so the budget must be estimated by the application, such as
+// from a quality setting. This is a best-effort signal for scaling down texture resolution: it
+// excludes memory allocated by the driver itself and by other applications, and integrated GPUs
+// share their memory with the rest of the system.
+func MemoryPressure(dev Instance, budget_bytes int) float64 {
+	if budget_bytes <= 0 {
+		return 1
+	}
+	used := float64(dev.GetMemoryUsage(Rendering.MemoryTotal))
+	return min(max(used/float64(budget_bytes), 0), 1)
+}
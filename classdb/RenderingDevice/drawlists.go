@@ -0,0 +1,45 @@
+package RenderingDevice
+
+import (
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Float"
+	"graphics.gd/variant/Packed"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+)
+
+// DrawListBeginBreadcrumb is like [Instance.DrawListBegin] but tags the draw list with the given
+// breadcrumb, so that GPU crash dumps identify the pass that the draw list belongs to. Extra data
+// can be packed into the lower 16 bits of the marker, see [Breadcrumb].
+//
+// The breadcrumb is fixed for the lifetime of the draw list, to mark separate regions, end the
+// draw list and begin a new one with a different breadcrumb.
+func (self Instance) DrawListBeginBreadcrumb(framebuffer RID.Framebuffer, breadcrumb Rendering.BreadcrumbMarker) int {
+	return int(Advanced(self).DrawListBegin(RID.Any(framebuffer), 0, Packed.New[Color.RGBA](), 1.0, 0, Rect2.PositionSize{}, int64(breadcrumb)))
+}
+
+// Breadcrumb composes a breadcrumb for [Expanded.DrawListBegin] from the marker of the pass,
+// stored in the upper 16 bits, and extra user data (such as the index of a cascade or a light),
+// of which only the lower 16 bits are kept so that it cannot corrupt the marker.
+func Breadcrumb(marker Rendering.BreadcrumbMarker, extra uint32) int {
+	return int(uint32(marker)&0xFFFF0000 | extra&0xFFFF)
+}
+
+// DrawListEnableScissorNormalized is like [Expanded.DrawListEnableScissor] but the rectangle is
+// given in normalized coordinates from (0, 0) at the top left to (1, 1) at the bottom right of the
+// target, which is target_size pixels large. The rectangle is expanded outwards to whole pixels and
+// clamped to the target, so that adjacent rectangles leave no gaps between them.
+func (self Instance) DrawListEnableScissorNormalized(draw_list int, rect Rect2.PositionSize, target_size Vector2i.XY) {
+	w, h := Float.X(target_size.X), Float.X(target_size.Y)
+	x0 := Float.Clamp(Float.Floor(rect.Position.X*w), 0, w)
+	y0 := Float.Clamp(Float.Floor(rect.Position.Y*h), 0, h)
+	x1 := Float.Clamp(Float.Ceil((rect.Position.X+rect.Size.X)*w), 0, w)
+	y1 := Float.Clamp(Float.Ceil((rect.Position.Y+rect.Size.Y)*h), 0, h)
+	Expanded(self).DrawListEnableScissor(draw_list, Rect2.PositionSize{
+		Position: Vector2.XY{X: x0, Y: y0},
+		Size:     Vector2.XY{X: max(x1-x0, 0), Y: max(y1-y0, 0)},
+	})
+}
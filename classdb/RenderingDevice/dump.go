@@ -0,0 +1,75 @@
+package RenderingDevice
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+// DumpBuffer writes the entire contents of the buffer to w, such as to a file, so that the output
+// of a compute shader can be inspected offline. It returns the number of bytes written.
+//
+// Note: This function blocks the GPU until the data is retrieved.
+func DumpBuffer(dev Instance, buffer RID.Buffer, w io.Writer) (int, error) {
+	data := dev.BufferGetData(buffer)
+	if data == nil {
+		return 0, fmt.Errorf("failed to read buffer %v", buffer)
+	}
+	return w.Write(data)
+}
+
+// TextureDumpHeader precedes the texel data written by [DumpTexture], encoded in little endian
+// byte order, such that it can be decoded with [encoding/binary.Read].
+type TextureDumpHeader struct {
+	Format uint32 // [Rendering.DataFormat] of the texels.
+	Width  uint32
+	Height uint32
+	Depth  uint32
+	Layers uint32 // number of layers that follow the header.
+	Size   uint32 // size of each layer in bytes.
+}
+
+// DumpTexture writes a [TextureDumpHeader] describing the texture to w, followed by the data of
+// each of its layers (as returned by [Instance.TextureGetData]). The texture must have been created
+// with [Rendering.TextureUsageCanCopyFromBit]. It returns the number of bytes written.
+//
+// Note: This function blocks the GPU until the data is retrieved.
+func DumpTexture(dev Instance, texture RID.Texture, w io.Writer) (int, error) {
+	format := dev.TextureGetFormat(texture)
+	if format.UsageBits()&Rendering.TextureUsageCanCopyFromBit == 0 {
+		return 0, fmt.Errorf("texture %v cannot be copied from", texture)
+	}
+	layers := make([][]byte, max(format.ArrayLayers(), 1))
+	for i := range layers {
+		layers[i] = dev.TextureGetData(texture, i)
+		if i > 0 && len(layers[i]) != len(layers[0]) {
+			return 0, fmt.Errorf("layer %v of texture %v is %v bytes, expected %v bytes", i, texture, len(layers[i]), len(layers[0]))
+		}
+	}
+	header, err := binary.Append(nil, binary.LittleEndian, TextureDumpHeader{
+		Format: uint32(format.Format()),
+		Width:  uint32(format.Width()),
+		Height: uint32(format.Height()),
+		Depth:  uint32(max(format.Depth(), 1)),
+		Layers: uint32(len(layers)),
+		Size:   uint32(len(layers[0])),
+	})
+	if err != nil {
+		return 0, err
+	}
+	total, err := w.Write(header)
+	if err != nil {
+		return total, err
+	}
+	for _, layer := range layers {
+		n, err := w.Write(layer)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,213 @@
+package RenderingDevice
+
+import (
+	"iter"
+	"slices"
+
+	"graphics.gd/classdb/Rendering"
+)
+
+// SupportedFormatsSeq returns an iterator over each [Rendering.DataFormat] that the device
+// supports for the given usage, in order of the format's value. The formats are queried
+// lazily, so breaking out of the loop early avoids querying the remaining formats.
+func SupportedFormatsSeq(dev Instance, usage Rendering.TextureUsageBits) iter.Seq[Rendering.DataFormat] {
+	return func(yield func(Rendering.DataFormat) bool) {
+		for format := Rendering.DataFormat(0); format < Rendering.DataFormatMax; format++ {
+			if dev.TextureIsFormatSupportedForUsage(format, usage) && !yield(format) {
+				return
+			}
+		}
+	}
+}
+
+// SupportedFormats returns each [Rendering.DataFormat] that the device supports for the
+// given usage, see [SupportedFormatsSeq].
+func SupportedFormats(dev Instance, usage Rendering.TextureUsageBits) []Rendering.DataFormat {
+	return slices.Collect(SupportedFormatsSeq(dev, usage))
+}
+
+// FormatSupport describes how a [Rendering.DataFormat] can be used on a device.
+type FormatSupport struct {
+	Sampling        bool // can be sampled in shaders.
+	ColorAttachment bool // can be rendered to as a color attachment.
+	DepthAttachment bool // can be rendered to as a depth/stencil attachment.
+	Storage         bool // can be read and written as a storage image.
+	Blit            bool // can be copied to and from.
+	LinearFilter    bool // can be sampled with [Rendering.SamplerFilterLinear].
+}
+
+// FormatCapabilities probes each relevant usage of the format on the device.
+func FormatCapabilities(dev Instance, format Rendering.DataFormat) FormatSupport {
+	return FormatSupport{
+		Sampling:        dev.TextureIsFormatSupportedForUsage(format, Rendering.TextureUsageSamplingBit),
+		ColorAttachment: dev.TextureIsFormatSupportedForUsage(format, Rendering.TextureUsageColorAttachmentBit),
+		DepthAttachment: dev.TextureIsFormatSupportedForUsage(format, Rendering.TextureUsageDepthStencilAttachmentBit),
+		Storage:         dev.TextureIsFormatSupportedForUsage(format, Rendering.TextureUsageStorageBit),
+		Blit:            dev.TextureIsFormatSupportedForUsage(format, Rendering.TextureUsageCanCopyFromBit|Rendering.TextureUsageCanCopyToBit),
+		LinearFilter:    dev.SamplerIsFormatSupportedForFilter(format, Rendering.SamplerFilterLinear),
+	}
+}
+
+// formatPixelSize returns the size in bytes of a single texel of the format, or zero for block
+// compressed, subsampled and multi-planar formats, which don't have a fixed texel size.
+func formatPixelSize(format Rendering.DataFormat) int {
+	switch {
+	case format == Rendering.DataFormatR4g4UnormPack8:
+		return 1
+	case format <= Rendering.DataFormatA1r5g5b5UnormPack16:
+		return 2
+	case format <= Rendering.DataFormatR8Srgb:
+		return 1
+	case format <= Rendering.DataFormatR8g8Srgb:
+		return 2
+	case format <= Rendering.DataFormatB8g8r8Srgb:
+		return 3
+	case format <= Rendering.DataFormatA2b10g10r10SintPack32:
+		return 4
+	case format <= Rendering.DataFormatR16Sfloat:
+		return 2
+	case format <= Rendering.DataFormatR16g16Sfloat:
+		return 4
+	case format <= Rendering.DataFormatR16g16b16Sfloat:
+		return 6
+	case format <= Rendering.DataFormatR16g16b16a16Sfloat:
+		return 8
+	case format <= Rendering.DataFormatR32Sfloat:
+		return 4
+	case format <= Rendering.DataFormatR32g32Sfloat:
+		return 8
+	case format <= Rendering.DataFormatR32g32b32Sfloat:
+		return 12
+	case format <= Rendering.DataFormatR32g32b32a32Sfloat:
+		return 16
+	case format <= Rendering.DataFormatR64Sfloat:
+		return 8
+	case format <= Rendering.DataFormatR64g64Sfloat:
+		return 16
+	case format <= Rendering.DataFormatR64g64b64Sfloat:
+		return 24
+	case format <= Rendering.DataFormatR64g64b64a64Sfloat:
+		return 32
+	case format <= Rendering.DataFormatE5b9g9r9UfloatPack32:
+		return 4
+	case format == Rendering.DataFormatD16Unorm:
+		return 2
+	case format <= Rendering.DataFormatD32Sfloat:
+		return 4
+	case format == Rendering.DataFormatS8Uint:
+		return 1
+	case format <= Rendering.DataFormatD24UnormS8Uint:
+		return 4
+	case format == Rendering.DataFormatD32SfloatS8Uint:
+		return 5
+	case format == Rendering.DataFormatR10x6UnormPack16, format == Rendering.DataFormatR12x4UnormPack16:
+		return 2
+	case format == Rendering.DataFormatR10x6g10x6Unorm2pack16, format == Rendering.DataFormatR12x4g12x4Unorm2pack16:
+		return 4
+	case format == Rendering.DataFormatR10x6g10x6b10x6a10x6Unorm4pack16, format == Rendering.DataFormatR12x4g12x4b12x4a12x4Unorm4pack16:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// astcBlockSizes are the block sizes of the ASTC formats, each of which has a UNORM and SRGB variant.
+var astcBlockSizes = [...][2]int{
+	{4, 4}, {5, 4}, {5, 5}, {6, 5}, {6, 6}, {8, 5}, {8, 6}, {8, 8},
+	{10, 5}, {10, 6}, {10, 8}, {10, 10}, {12, 10}, {12, 12},
+}
+
+// formatBlockSize returns the width and height in texels, and the size in bytes, of a block of the
+// block compressed format, or zero for other formats.
+func formatBlockSize(format Rendering.DataFormat) (width, height, size int) {
+	switch {
+	case format < Rendering.DataFormatBc1RgbUnormBlock:
+		return 0, 0, 0
+	case format <= Rendering.DataFormatBc1RgbaSrgbBlock:
+		return 4, 4, 8
+	case format <= Rendering.DataFormatBc3SrgbBlock:
+		return 4, 4, 16
+	case format <= Rendering.DataFormatBc4SnormBlock:
+		return 4, 4, 8
+	case format <= Rendering.DataFormatBc7SrgbBlock:
+		return 4, 4, 16
+	case format <= Rendering.DataFormatEtc2R8g8b8a1SrgbBlock:
+		return 4, 4, 8
+	case format <= Rendering.DataFormatEtc2R8g8b8a8SrgbBlock:
+		return 4, 4, 16
+	case format <= Rendering.DataFormatEacR11SnormBlock:
+		return 4, 4, 8
+	case format <= Rendering.DataFormatEacR11g11SnormBlock:
+		return 4, 4, 16
+	case format <= Rendering.DataFormatAstc12x12SrgbBlock:
+		block := astcBlockSizes[(format-Rendering.DataFormatAstc4x4UnormBlock)/2]
+		return block[0], block[1], 16
+	default:
+		return 0, 0, 0
+	}
+}
+
+// formatImageSize returns the size in bytes of a single layer and mipmap of the format with the
+// given width and height, or zero for subsampled and multi-planar formats.
+func formatImageSize(format Rendering.DataFormat, width, height int) int {
+	if texel := formatPixelSize(format); texel != 0 {
+		return width * height * texel
+	}
+	if bw, bh, size := formatBlockSize(format); size != 0 {
+		return ((width + bw - 1) / bw) * ((height + bh - 1) / bh) * size
+	}
+	return 0
+}
+
+// PreferredCompressedFormat returns the best block compressed format that the device can sample,
+// for runtime texture compression of images with or without alpha, or of HDR images. BC formats
+// (usually supported on desktop) are preferred, followed by ASTC and ETC2 (usually supported on
+// mobile). Returns false if the device supports none of the candidates.
+func PreferredCompressedFormat(dev Instance, has_alpha bool, hdr bool) (Rendering.DataFormat, bool) {
+	var candidates []Rendering.DataFormat
+	switch {
+	case hdr:
+		candidates = []Rendering.DataFormat{Rendering.DataFormatBc6hUfloatBlock}
+	case has_alpha:
+		candidates = []Rendering.DataFormat{
+			Rendering.DataFormatBc7UnormBlock,
+			Rendering.DataFormatAstc4x4UnormBlock,
+			Rendering.DataFormatEtc2R8g8b8a8UnormBlock,
+			Rendering.DataFormatBc3UnormBlock,
+		}
+	default:
+		candidates = []Rendering.DataFormat{
+			Rendering.DataFormatBc7UnormBlock,
+			Rendering.DataFormatAstc4x4UnormBlock,
+			Rendering.DataFormatEtc2R8g8b8UnormBlock,
+			Rendering.DataFormatBc1RgbUnormBlock,
+		}
+	}
+	for _, format := range candidates {
+		if dev.TextureIsFormatSupportedForUsage(format, Rendering.TextureUsageSamplingBit) {
+			return format, true
+		}
+	}
+	return 0, false
+}
+
+// integerFormats lists the unsigned integer color formats, each of which is immediately followed
+// by its signed counterpart.
+var integerFormats = []Rendering.DataFormat{
+	Rendering.DataFormatR8Uint, Rendering.DataFormatR8g8Uint, Rendering.DataFormatR8g8b8Uint,
+	Rendering.DataFormatB8g8r8Uint, Rendering.DataFormatR8g8b8a8Uint, Rendering.DataFormatB8g8r8a8Uint,
+	Rendering.DataFormatA8b8g8r8UintPack32, Rendering.DataFormatA2r10g10b10UintPack32, Rendering.DataFormatA2b10g10r10UintPack32,
+	Rendering.DataFormatR16Uint, Rendering.DataFormatR16g16Uint, Rendering.DataFormatR16g16b16Uint, Rendering.DataFormatR16g16b16a16Uint,
+	Rendering.DataFormatR32Uint, Rendering.DataFormatR32g32Uint, Rendering.DataFormatR32g32b32Uint, Rendering.DataFormatR32g32b32a32Uint,
+	Rendering.DataFormatR64Uint, Rendering.DataFormatR64g64Uint, Rendering.DataFormatR64g64b64Uint, Rendering.DataFormatR64g64b64a64Uint,
+}
+
+// FormatSupportsBlending reports whether the format can be used as a color attachment of a pipeline
+// with blending enabled, such as for transparent rendering. Integer formats can never be blended
+// and enabling blending on them results in validation errors.
+func FormatSupportsBlending(dev Instance, format Rendering.DataFormat) bool {
+	if slices.Contains(integerFormats, format) || slices.Contains(integerFormats, format-1) {
+		return false
+	}
+	return dev.TextureIsFormatSupportedForUsage(format, Rendering.TextureUsageColorAttachmentBit)
+}
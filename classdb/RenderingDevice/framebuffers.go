@@ -0,0 +1,233 @@
+package RenderingDevice
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"graphics.gd/classdb/RDAttachmentFormat"
+	"graphics.gd/classdb/RDFramebufferPass"
+	"graphics.gd/classdb/Rendering"
+	gd "graphics.gd/internal"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/RID"
+)
+
+// FramebufferPass returns a pass for use with [Instance.FramebufferCreateMultipass], that
+// writes to the given color attachments and depth attachment while reading from the given input
+// attachments (written to by earlier passes). Each attachment is specified by its index in the
+// framebuffer, use [RDFramebufferPass.AttachmentUnused] for depth_attachment when the pass does
+// not use a depth attachment.
+func FramebufferPass(color_attachments []int, depth_attachment int, input_attachments []int) RDFramebufferPass.Instance {
+	pass := RDFramebufferPass.New()
+	pass.SetColorAttachments(attachmentIndices(color_attachments))
+	pass.SetInputAttachments(attachmentIndices(input_attachments))
+	pass.SetDepthAttachment(depth_attachment)
+	return pass
+}
+
+func attachmentIndices(indices []int) []int32 {
+	converted := make([]int32, len(indices))
+	for i, index := range indices {
+		converted[i] = int32(index)
+	}
+	return converted
+}
+
+// attachmentLayout identifies a framebuffer format by the attachments it was created from.
+type attachmentLayout struct {
+	attachments string
+	views       int
+}
+
+// CachedFramebufferFormat is like [Expanded.FramebufferFormatCreate] but remembers the format ID for
+// each distinct list of attachments and view count, so that render targets sharing an attachment
+// layout only create the format once. Format IDs are never freed, so they are safe to cache for the
+// lifetime of the device.
+func (self Instance) CachedFramebufferFormat(attachments []RDAttachmentFormat.Instance, view_count int) int {
+	var key strings.Builder
+	for _, attachment := range attachments {
+		fmt.Fprintf(&key, "%d:%d:%d;", attachment.Format(), attachment.Samples(), attachment.UsageFlags())
+	}
+	layout := attachmentLayout{attachments: key.String(), views: view_count}
+	s := stateOf(self)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if format, ok := s.formats[layout]; ok {
+		return format
+	}
+	format := Expanded(self).FramebufferFormatCreate(attachments, view_count)
+	if format < 0 {
+		return format
+	}
+	if s.formats == nil {
+		s.formats = make(map[attachmentLayout]int)
+	}
+	s.formats[layout] = format
+	return format
+}
+
+// FramebufferCreateValidated creates a framebuffer from the textures, after validating that they
+// share the same dimensions and are compatible with the expected framebuffer format. An error is
+// returned when they are not, rather than an invalid framebuffer that only fails once it is used.
+func FramebufferCreateValidated(dev Instance, textures []RID.Texture, expected_format int) (RID.Framebuffer, error) {
+	if expected_format < 0 {
+		return RID.Framebuffer(0), fmt.Errorf("invalid framebuffer format %v", expected_format)
+	}
+	if err := ValidateFramebufferTextures(dev, textures); err != nil {
+		return RID.Framebuffer(0), err
+	}
+	framebuffer := RID.Framebuffer(Advanced(dev).FramebufferCreate(gd.ArrayFromSlice[Array.Contains[RID.Any]](textures), int64(expected_format), 1))
+	if framebuffer == 0 || !dev.FramebufferIsValid(framebuffer) {
+		return RID.Framebuffer(0), fmt.Errorf("textures %v do not match framebuffer format %v", textures, expected_format)
+	}
+	stateOf(dev).target(framebuffer, textures)
+	return framebuffer, nil
+}
+
+// FramebufferCreateMRT creates a framebuffer that renders to multiple color textures at once, such
+// as a G-buffer, with a shared depth texture (which may be zero, for none). The color attachments
+// come first, in order, followed by the depth attachment. An error is returned if the textures
+// don't all have the same dimensions.
+func FramebufferCreateMRT(dev Instance, colors []RID.Texture, depth RID.Texture) (RID.Framebuffer, error) {
+	if len(colors) == 0 {
+		return RID.Framebuffer(0), errors.New("no color attachments")
+	}
+	textures := append(slices.Clip(colors), depth)
+	if depth == 0 {
+		textures = colors
+	}
+	if err := ValidateFramebufferTextures(dev, textures); err != nil {
+		return RID.Framebuffer(0), err
+	}
+	framebuffer := RID.Framebuffer(Advanced(dev).FramebufferCreate(gd.ArrayFromSlice[Array.Contains[RID.Any]](textures), -1, 1))
+	if framebuffer == 0 {
+		return framebuffer, errors.New("failed to create framebuffer")
+	}
+	stateOf(dev).target(framebuffer, textures)
+	return framebuffer, nil
+}
+
+// ValidateFramebufferTextures returns an error naming the first of the textures whose width and
+// height differ from those of the first texture, as all attachments of a framebuffer must have the
+// same dimensions.
+func ValidateFramebufferTextures(dev Instance, textures []RID.Texture) error {
+	if len(textures) == 0 {
+		return nil
+	}
+	first := TextureSize(dev, textures[0])
+	for i, texture := range textures[1:] {
+		if size := TextureSize(dev, texture); size != first {
+			return fmt.Errorf("attachment %d (texture %v) is %vx%v, but attachment 0 is %vx%v", i+1, texture, size.X, size.Y, first.X, first.Y)
+		}
+	}
+	return nil
+}
+
+// target records the attachments of a framebuffer created by this package.
+func (s *state) target(framebuffer RID.Framebuffer, textures []RID.Texture) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.targets == nil {
+		s.targets = make(map[RID.Framebuffer][]RID.Texture)
+	}
+	s.targets[framebuffer] = slices.Clone(textures)
+}
+
+// drawFlagColors is the number of color attachments that [Rendering.DrawFlags] has bits for.
+const drawFlagColors = 8
+
+// attachmentFlags returns the draw flags that apply the given color, depth and stencil flags to
+// each attachment of the framebuffer, or false if the attachments of the framebuffer are unknown,
+// or if it has more color attachments than the draw flags can refer to.
+func attachmentFlags(dev Instance, framebuffer RID.Framebuffer, color0, depth, stencil Rendering.DrawFlags) (Rendering.DrawFlags, bool) {
+	s := stateOf(dev)
+	s.mutex.Lock()
+	textures, ok := s.targets[framebuffer]
+	s.mutex.Unlock()
+	if !ok {
+		return 0, false
+	}
+	var flags Rendering.DrawFlags
+	var colors int
+	for _, texture := range textures {
+		format := dev.TextureGetFormat(texture)
+		if format.UsageBits()&Rendering.TextureUsageDepthStencilAttachmentBit == 0 {
+			if colors == drawFlagColors {
+				return 0, false
+			}
+			flags |= color0 << colors
+			colors++
+			continue
+		}
+		switch format.Format() {
+		case Rendering.DataFormatS8Uint:
+			flags |= stencil
+		case Rendering.DataFormatD16UnormS8Uint, Rendering.DataFormatD24UnormS8Uint, Rendering.DataFormatD32SfloatS8Uint:
+			flags |= depth | stencil
+		default:
+			flags |= depth
+		}
+	}
+	return flags, true
+}
+
+// ClearAllFlags returns the draw flags for [Expanded.DrawListBegin] that clear every attachment
+// of the framebuffer. The attachments of framebuffers created with [FramebufferCreateValidated]
+// or [FramebufferCreateMRT] are known, so only their flags are set. Otherwise, the attachments
+// can only be guessed at, so [Rendering.DrawClearAll] is returned along with false.
+func ClearAllFlags(dev Instance, framebuffer RID.Framebuffer) (Rendering.DrawFlags, bool) {
+	if flags, ok := attachmentFlags(dev, framebuffer, Rendering.DrawClearColor0, Rendering.DrawClearDepth, Rendering.DrawClearStencil); ok {
+		return flags, true
+	}
+	return Rendering.DrawClearAll, false
+}
+
+// IgnoreAllFlags returns the draw flags for [Expanded.DrawListBegin] that ignore (discard) the
+// previous contents of every attachment of the framebuffer. As with [ClearAllFlags], false is
+// returned along with [Rendering.DrawIgnoreAll] when the attachments of the framebuffer are unknown.
+func IgnoreAllFlags(dev Instance, framebuffer RID.Framebuffer) (Rendering.DrawFlags, bool) {
+	if flags, ok := attachmentFlags(dev, framebuffer, Rendering.DrawIgnoreColor0, Rendering.DrawIgnoreDepth, Rendering.DrawIgnoreStencil); ok {
+		return flags, true
+	}
+	return Rendering.DrawIgnoreAll, false
+}
+
+// screenFormats are the color formats that swapchains are commonly created with.
+var screenFormats = []Rendering.DataFormat{
+	Rendering.DataFormatB8g8r8a8Unorm,
+	Rendering.DataFormatR8g8b8a8Unorm,
+	Rendering.DataFormatB8g8r8a8Srgb,
+	Rendering.DataFormatR8g8b8a8Srgb,
+	Rendering.DataFormatA2b10g10r10UnormPack32,
+	Rendering.DataFormatA2r10g10b10UnormPack32,
+	Rendering.DataFormatR16g16b16a16Sfloat,
+}
+
+// ScreenColorFormat returns the color format of the given screen's framebuffer, so that shaders
+// drawing to the screen can tell whether their output will be sRGB encoded on write (and must then
+// not be gamma corrected again). The framebuffer format ID returned by [Expanded.ScreenGetFramebufferFormat]
+// is opaque, so it is compared against the single color attachment format of each of the common
+// swapchain formats. Returns false if there is no main rendering device, or if the format is not
+// one of the common ones.
+func ScreenColorFormat(screen int) (Rendering.DataFormat, bool) {
+	dev, ok := mainDevice()
+	if !ok {
+		return 0, false
+	}
+	format := Expanded(dev).ScreenGetFramebufferFormat(screen)
+	if format < 0 {
+		return 0, false
+	}
+	for _, candidate := range screenFormats {
+		attachment := RDAttachmentFormat.New()
+		attachment.SetFormat(candidate)
+		attachment.SetSamples(Rendering.TextureSamples1)
+		attachment.SetUsageFlags(int(Rendering.TextureUsageColorAttachmentBit))
+		if dev.CachedFramebufferFormat([]RDAttachmentFormat.Instance{attachment}, 1) == format {
+			return candidate, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,56 @@
+package RenderingDevice
+
+import (
+	"errors"
+
+	"graphics.gd/classdb/Engine"
+)
+
+// SubmitTracked is like [Instance.Submit] but also advances the frame index of the local device,
+// as returned by [Instance.CurrentFrame], frees any garbage collected [OwnedRID] of the device and
+// first creates the pipelines queued by [RenderPipelineCreateAsync].
+func (self Instance) SubmitTracked() {
+	s := stateOf(self)
+	s.mutex.Lock()
+	s.frames++
+	queued := s.queued
+	s.queued = nil
+	s.mutex.Unlock()
+	for _, f := range queued {
+		f()
+	}
+	s.collect(self)
+	self.Submit()
+}
+
+// CurrentFrame returns a monotonically increasing index for the frame that the device is currently
+// recording, so that async callbacks and ring buffers can be correlated with the frame that made
+// a request. For the main rendering device, this is the number of frames drawn by the engine, for
+// local devices, it is the number of times that [Instance.SubmitTracked] has been called.
+func (self Instance) CurrentFrame() int64 {
+	if isMainDevice(self) {
+		return int64(Engine.GetFramesDrawn())
+	}
+	s := stateOf(self)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.frames
+}
+
+// ErrNotLocalDevice is returned when an operation that only applies to local devices (see
+// [NewLocalDevice]) is attempted on the main rendering device.
+var ErrNotLocalDevice = errors.New("the main rendering device cannot be submitted or synced")
+
+// SubmitAndSync submits the work recorded on a local device (see [Instance.SubmitTracked]) and then
+// waits for the GPU to finish it, as [Instance.Sync] may only be called after [Instance.Submit].
+// [ErrNotLocalDevice] is returned for the main rendering device, which the engine submits and
+// syncs once per frame by itself. Submitting when no work has been recorded is not an error, as the
+// engine does not report whether any work is pending, so an empty submission is simply waited on.
+func (self Instance) SubmitAndSync() error {
+	if isMainDevice(self) {
+		return ErrNotLocalDevice
+	}
+	self.SubmitTracked()
+	self.Sync()
+	return nil
+}
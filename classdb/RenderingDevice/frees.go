@@ -0,0 +1,146 @@
+package RenderingDevice
+
+import (
+	"fmt"
+
+	"graphics.gd/classdb/Engine"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+// freedLimit is the number of most recently freed resources remembered by each device with
+// [Instance.EnableFreeTracking].
+const freedLimit = 4096
+
+// EnableFreeTracking makes the device remember the resources freed with [Instance.FreeRid] (which
+// the helpers in this package also use), so that freeing the same resource again raises a warning
+// instead of corrupting the device, or panics if panics is true (as is useful in tests). This
+// catches resources that are freed both by their owner and by a pool, for example. Only the most
+// recently freed resources are remembered, so that tracking does not grow without bound.
+func (self Instance) EnableFreeTracking(panics bool) {
+	s := stateOf(self)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.freed == nil {
+		s.freed = make(map[RID.Any]struct{})
+	}
+	s.panics = panics
+}
+
+// freeing records that the resource is about to be freed, forgetting what this package knows
+// about it, and reports false (after raising a warning or panicking) if free tracking is enabled
+// and it has already been freed.
+func (self Instance) freeing(rid RID.Any) bool {
+	v, ok := states.Load(self.ID())
+	if !ok {
+		return true
+	}
+	s := v.(*state)
+	s.mutex.Lock()
+	_, freed := s.freed[rid]
+	if !freed {
+		s.forget(rid)
+	}
+	if !freed && s.freed != nil {
+		if len(s.history) < freedLimit {
+			s.history = append(s.history, rid)
+		} else {
+			delete(s.freed, s.history[s.oldest])
+			s.history[s.oldest] = rid
+			s.oldest = (s.oldest + 1) % freedLimit
+		}
+		s.freed[rid] = struct{}{}
+	}
+	panics := s.panics
+	s.mutex.Unlock()
+	if freed {
+		msg := fmt.Sprintf("RenderingDevice: resource %v has already been freed", rid)
+		if panics {
+			panic(msg)
+		}
+		Engine.RaiseWarning(msg)
+		return false
+	}
+	return true
+}
+
+// forget removes the resource from the state, so that nothing is known about it once freed. The
+// state must be locked.
+func (s *state) forget(rid RID.Any) {
+	delete(s.tracked, rid)
+	delete(s.shaders, RID.Shader(rid))
+	delete(s.sizes, rid)
+	delete(s.targets, RID.Framebuffer(rid))
+	delete(s.sets, RID.UniformSet(rid))
+	for name, k := range s.kernels {
+		if RID.Any(k.shader) == rid || RID.Any(k.pipeline) == rid {
+			delete(s.kernels, name)
+		}
+	}
+}
+
+// FreeRidTracked frees each of the resources with [Instance.FreeRid], so that resources that have
+// already been freed are detected once [Instance.EnableFreeTracking] has been called.
+func (self Instance) FreeRidTracked(rids ...RID.Any) {
+	for _, rid := range rids {
+		self.FreeRid(rid)
+	}
+}
+
+// Resource is any of the kinds of resources that can be created on a device.
+type Resource interface {
+	RID.Texture | RID.Framebuffer | RID.Sampler | RID.VertexBuffer | RID.VertexArray |
+		RID.IndexBuffer | RID.IndexArray | RID.Shader | RID.StorageBuffer | RID.TextureBuffer |
+		RID.UniformBuffer | RID.UniformSet | RID.Buffer | RID.RenderPipeline | RID.ComputePipeline
+}
+
+// FreeRidTyped is like [Instance.FreeRidTracked], but once [Instance.EnableFreeTracking] has been
+// called, it also checks that the resource is still valid as the kind of resource given, so that
+// passing a framebuffer where a texture was meant (for example) raises a warning instead of
+// freeing the wrong resource.
+func FreeRidTyped[T Resource](dev Instance, rid T) {
+	s := stateOf(dev)
+	s.mutex.Lock()
+	tracking := s.freed != nil
+	_, freed := s.freed[RID.Any(rid)]
+	panics := s.panics
+	s.mutex.Unlock()
+	if tracking && !freed && !isValidResource(dev, rid) {
+		msg := fmt.Sprintf("RenderingDevice: resource %v is not a valid %T", rid, rid)
+		if panics {
+			panic(msg)
+		}
+		Engine.RaiseWarning(msg)
+		return
+	}
+	dev.FreeRidTracked(RID.Any(rid))
+}
+
+// SetResourceNameTyped is like [Instance.SetResourceName] but only accepts device resources.
+func SetResourceNameTyped[T Resource](dev Instance, rid T, name string) {
+	dev.SetResourceName(RID.Any(rid), name)
+}
+
+// GetDriverResourceTyped is like [Instance.GetDriverResource] but only accepts device resources.
+func GetDriverResourceTyped[T Resource](dev Instance, resource Rendering.DriverResource, rid T, index int) int {
+	return dev.GetDriverResource(resource, RID.Any(rid), index)
+}
+
+// isValidResource reports whether the resource is valid as its kind of resource, for the kinds
+// that the device can validate.
+func isValidResource[T Resource](dev Instance, rid T) bool {
+	switch rid := any(rid).(type) {
+	case RID.Texture:
+		return dev.TextureIsValid(rid)
+	case RID.Framebuffer:
+		return dev.FramebufferIsValid(rid)
+	case RID.UniformSet:
+		return dev.UniformSetIsValid(rid)
+	case RID.RenderPipeline:
+		return dev.RenderPipelineIsValid(rid)
+	case RID.ComputePipeline:
+		return dev.ComputePipelineIsValid(rid)
+	default:
+		return true
+	}
+}
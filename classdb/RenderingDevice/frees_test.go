@@ -0,0 +1,32 @@
+package RenderingDevice
+
+import (
+	"testing"
+
+	"graphics.gd/variant/RID"
+)
+
+func TestStateForget(t *testing.T) {
+	s := new(state)
+	s.track(1, 2, 3, 4, 5)
+	s.shaders = map[RID.Shader]ShaderReflection{2: {}}
+	s.sizes = map[RID.Any]int{3: 16}
+	s.targets = map[RID.Framebuffer][]RID.Texture{4: {1}}
+	s.sets = map[RID.UniformSet]UniformSetInfo{5: {}}
+	s.kernels = map[string]kernel{"blit": {shader: 2, pipeline: 6}, "reduce": {shader: 7, pipeline: 8}}
+	for _, rid := range []RID.Any{1, 2, 3, 4, 5} {
+		s.forget(rid)
+		if s.owns(rid) {
+			t.Errorf("resource %v is still tracked after being forgotten", rid)
+		}
+	}
+	if len(s.shaders) != 0 || len(s.sizes) != 0 || len(s.targets) != 0 || len(s.sets) != 0 {
+		t.Errorf("stale state remains: %v %v %v %v", s.shaders, s.sizes, s.targets, s.sets)
+	}
+	if _, ok := s.kernels["blit"]; ok {
+		t.Error("kernel using a freed shader is still cached")
+	}
+	if _, ok := s.kernels["reduce"]; !ok {
+		t.Error("unrelated kernel was forgotten")
+	}
+}
@@ -0,0 +1,87 @@
+package RenderingDevice
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"graphics.gd/classdb/RDSamplerState"
+	"graphics.gd/classdb/RDUniform"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+const histogramGLSL = `#version 450
+layout(local_size_x = 16, local_size_y = 16, local_size_z = 1) in;
+layout(set = 0, binding = 0) uniform sampler2D source;
+layout(set = 0, binding = 1, std430) restrict buffer Histogram { uint bins[]; } histogram;
+layout(push_constant, std430) uniform Params { uint bins; float max_luminance; uint pad0; uint pad1; } params;
+void main() {
+	ivec2 size = textureSize(source, 0);
+	ivec2 texel = ivec2(gl_GlobalInvocationID.xy);
+	if (texel.x >= size.x || texel.y >= size.y) {
+		return;
+	}
+	vec3 color = texelFetch(source, texel, 0).rgb;
+	float luminance = clamp(dot(color, vec3(0.2126, 0.7152, 0.0722)) / params.max_luminance, 0.0, 1.0);
+	uint bin = min(uint(luminance * float(params.bins)), params.bins - 1u);
+	atomicAdd(histogram.bins[bin], 1u);
+}
+`
+
+// Histogram returns a luminance histogram of the texture with the given number of bins,
+// computed on the GPU. The bins evenly divide the luminance range [0, 1], which suits LDR
+// textures, luminance values above 1 are counted in the last bin. Use [HistogramRange] for
+// HDR textures. The texture must have been created with [Rendering.TextureUsageSamplingBit].
+func Histogram(dev Instance, texture RID.Texture, bins int) ([]uint32, error) {
+	return HistogramRange(dev, texture, bins, 1)
+}
+
+// HistogramRange is like [Histogram], except that the bins evenly divide the luminance range
+// [0, maxLuminance], so that HDR float textures can be measured for auto-exposure and
+// tone-mapping. Luminance values above maxLuminance are counted in the last bin.
+func HistogramRange(dev Instance, texture RID.Texture, bins int, maxLuminance float32) ([]uint32, error) {
+	if bins <= 0 {
+		return nil, errors.New("histogram must have at least one bin")
+	}
+	if !(maxLuminance > 0) {
+		return nil, fmt.Errorf("histogram maximum luminance must be positive, got %v", maxLuminance)
+	}
+	format := dev.TextureGetFormat(texture)
+	if format.UsageBits()&Rendering.TextureUsageSamplingBit == 0 {
+		return nil, fmt.Errorf("texture %v cannot be sampled", texture)
+	}
+	k, err := stateOf(dev).kernel(dev, "histogram", histogramGLSL)
+	if err != nil {
+		return nil, err
+	}
+	sampler := dev.SamplerCreate(RDSamplerState.New())
+	defer dev.FreeRidTracked(RID.Any(sampler))
+	buffer := Expanded(dev).StorageBufferCreate(bins*4, make([]byte, bins*4), 0, 0)
+	defer dev.FreeRidTracked(RID.Any(buffer))
+	set := dev.UniformSetCreate([]RDUniform.Instance{
+		uniform(Rendering.UniformTypeSamplerWithTexture, 0, RID.Any(sampler), RID.Any(texture)),
+		uniform(Rendering.UniformTypeStorageBuffer, 1, RID.Any(buffer)),
+	}, k.shader, 0)
+	if set == RID.UniformSet(0) {
+		return nil, fmt.Errorf("failed to create histogram uniform set for texture %v", texture)
+	}
+	defer dev.FreeRidTracked(RID.Any(set))
+	push := pushConstant(uint32(bins), math.Float32bits(maxLuminance))
+	list := dev.ComputeListBegin()
+	dev.ComputeListBindComputePipeline(list, k.pipeline)
+	dev.ComputeListBindUniformSet(list, set, 0)
+	dev.ComputeListSetPushConstant(list, push, len(push))
+	dev.ComputeListDispatch(list, groups(format.Width(), 16), groups(format.Height(), 16), 1)
+	dev.ComputeListEnd()
+	data := dev.BufferGetData(RID.Buffer(buffer))
+	if len(data) != bins*4 {
+		return nil, errors.New("failed to read back histogram")
+	}
+	result := make([]uint32, bins)
+	for i := range result {
+		result[i] = binary.LittleEndian.Uint32(data[i*4:])
+	}
+	return result, nil
+}
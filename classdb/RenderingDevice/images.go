@@ -0,0 +1,91 @@
+package RenderingDevice
+
+import (
+	"errors"
+	"fmt"
+
+	"graphics.gd/classdb/Image"
+	"graphics.gd/classdb/RDTextureFormat"
+	"graphics.gd/classdb/RDTextureView"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+// imageFormats maps each [Image.Format] that can be uploaded as-is to its [Rendering.DataFormat].
+var imageFormats = map[Image.Format]Rendering.DataFormat{
+	Image.FormatR8:       Rendering.DataFormatR8Unorm,
+	Image.FormatRg8:      Rendering.DataFormatR8g8Unorm,
+	Image.FormatRgba8:    Rendering.DataFormatR8g8b8a8Unorm,
+	Image.FormatRf:       Rendering.DataFormatR32Sfloat,
+	Image.FormatRgf:      Rendering.DataFormatR32g32Sfloat,
+	Image.FormatRgbaf:    Rendering.DataFormatR32g32b32a32Sfloat,
+	Image.FormatRh:       Rendering.DataFormatR16Sfloat,
+	Image.FormatRgh:      Rendering.DataFormatR16g16Sfloat,
+	Image.FormatRgbah:    Rendering.DataFormatR16g16b16a16Sfloat,
+	Image.FormatRgbe9995: Rendering.DataFormatE5b9g9r9UfloatPack32,
+}
+
+// TextureFromGodotImage creates a 2D texture from the image, including its mipmaps. Images in
+// formats without a widely supported equivalent, such as luminance or three channel formats, are
+// first converted to a four channel format, and compressed images are decompressed. The image
+// itself is left unmodified.
+func TextureFromGodotImage(dev Instance, img Image.Instance) (RID.Texture, error) {
+	width, height := img.GetWidth(), img.GetHeight()
+	if width == 0 || height == 0 {
+		return RID.Texture(0), errors.New("image is empty")
+	}
+	format, ok := imageFormats[img.GetFormat()]
+	if !ok {
+		img = Image.CreateFromData(width, height, img.HasMipmaps(), img.GetFormat(), img.GetData())
+		if img.IsCompressed() {
+			if err := img.Decompress(); err != nil {
+				return RID.Texture(0), fmt.Errorf("failed to decompress image: %w", err)
+			}
+		}
+		switch img.GetFormat() {
+		case Image.FormatRgbf:
+			img.Convert(Image.FormatRgbaf)
+		case Image.FormatRgbh:
+			img.Convert(Image.FormatRgbah)
+		default:
+			img.Convert(Image.FormatRgba8)
+		}
+		if format, ok = imageFormats[img.GetFormat()]; !ok {
+			return RID.Texture(0), fmt.Errorf("image format %v is not supported", img.GetFormat())
+		}
+	}
+	tf := RDTextureFormat.New()
+	tf.SetTextureType(Rendering.TextureType2d)
+	tf.SetFormat(format)
+	tf.SetWidth(width)
+	tf.SetHeight(height)
+	tf.SetMipmaps(img.GetMipmapCount() + 1)
+	tf.SetUsageBits(Rendering.TextureUsageSamplingBit | Rendering.TextureUsageCanUpdateBit | Rendering.TextureUsageCanCopyFromBit)
+	texture := Expanded(dev).TextureCreate(tf, RDTextureView.New(), [][]byte{img.GetData()})
+	if texture == RID.Texture(0) {
+		return texture, fmt.Errorf("failed to create %vx%v texture", width, height)
+	}
+	return texture, nil
+}
+
+// TextureToGodotImage reads back the first layer of the texture, including its mipmaps, as an
+// image. The texture must have been created with [Rendering.TextureUsageCanCopyFromBit] and be in
+// one of the data formats that [TextureFromGodotImage] creates textures with.
+//
+// Note: This function blocks the GPU until the data is retrieved.
+func TextureToGodotImage(dev Instance, texture RID.Texture) (Image.Instance, error) {
+	format := dev.TextureGetFormat(texture)
+	if format.UsageBits()&Rendering.TextureUsageCanCopyFromBit == 0 {
+		return Image.Instance{}, fmt.Errorf("texture %v cannot be copied from", texture)
+	}
+	for image_format, data_format := range imageFormats {
+		if data_format == format.Format() {
+			img := Image.CreateFromData(format.Width(), format.Height(), format.Mipmaps() > 1, image_format, dev.TextureGetData(texture, 0))
+			if img.GetWidth() == 0 {
+				return Image.Instance{}, fmt.Errorf("failed to read back texture %v", texture)
+			}
+			return img, nil
+		}
+	}
+	return Image.Instance{}, fmt.Errorf("data format %v has no equivalent image format", format.Format())
+}
@@ -0,0 +1,69 @@
+package RenderingDevice
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"graphics.gd/classdb/RDShaderSPIRV"
+	"graphics.gd/classdb/RDShaderSource"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/String"
+)
+
+// CompileGLSLWithIncludes compiles the GLSL source for the given shader stage into SPIR-V, after
+// replacing each #include "path" (or #include <path>) directive with the source returned by resolve
+// for that path, recursively. This makes it possible to share code between shaders, with includes
+// loaded from res://, disk or an embedded filesystem. An error is returned for circular includes
+// and for compilation failures. There is no support for #pragma once, so a file that is included
+// more than once (without a cycle, such as by two other includes) is pasted each time, and should
+// be wrapped in an #ifndef include guard if it declares anything.
+func CompileGLSLWithIncludes(dev Instance, source string, resolve func(path string) (string, error), stage Rendering.ShaderStage) (RDShaderSPIRV.Instance, error) {
+	expanded, err := expandIncludes(source, resolve, nil)
+	if err != nil {
+		return RDShaderSPIRV.Instance{}, err
+	}
+	shader := RDShaderSource.New()
+	RDShaderSource.Advanced(shader).SetStageSource(stage, String.New(expanded))
+	spirv := dev.ShaderCompileSpirvFromSource(shader)
+	if msg := RDShaderSPIRV.Advanced(spirv).GetStageCompileError(stage).String(); msg != "" {
+		return spirv, errors.New(msg)
+	}
+	return spirv, nil
+}
+
+// expandIncludes replaces the #include directives in the source, stack holds the paths of the
+// includes currently being expanded, to detect cycles.
+func expandIncludes(source string, resolve func(path string) (string, error), stack []string) (string, error) {
+	var out strings.Builder
+	for line := range strings.Lines(source) {
+		directive, ok := strings.CutPrefix(strings.TrimSpace(line), "#include")
+		if !ok {
+			out.WriteString(line)
+			continue
+		}
+		directive = strings.TrimSpace(directive)
+		if len(directive) < 2 || !(directive[0] == '"' && directive[len(directive)-1] == '"' ||
+			directive[0] == '<' && directive[len(directive)-1] == '>') {
+			return "", fmt.Errorf("malformed include directive %q", strings.TrimSpace(line))
+		}
+		path := directive[1 : len(directive)-1]
+		if slices.Contains(stack, path) {
+			return "", fmt.Errorf("circular include: %s -> %s", strings.Join(stack, " -> "), path)
+		}
+		included, err := resolve(path)
+		if err != nil {
+			return "", fmt.Errorf("include %q: %w", path, err)
+		}
+		included, err = expandIncludes(included, resolve, append(stack, path))
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(included)
+		if !strings.HasSuffix(included, "\n") {
+			out.WriteByte('\n')
+		}
+	}
+	return out.String(), nil
+}
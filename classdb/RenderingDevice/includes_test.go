@@ -0,0 +1,95 @@
+package RenderingDevice
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// includeFiles returns a resolver for expandIncludes that reads the given files.
+func includeFiles(files map[string]string) func(string) (string, error) {
+	return func(path string) (string, error) {
+		source, ok := files[path]
+		if !ok {
+			return "", fmt.Errorf("no such file %s", path)
+		}
+		return source, nil
+	}
+}
+
+func TestExpandIncludes(t *testing.T) {
+	resolve := includeFiles(map[string]string{
+		"common.glsl":   "#include \"math.glsl\"\nconst float scale = 2.0;",
+		"math.glsl":     "const float pi = 3.14159;\n",
+		"lib/util.glsl": "float half(float x) { return x * 0.5; }\n",
+	})
+	for _, test := range []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "none",
+			source: "#version 450\nvoid main() {}\n",
+			want:   "#version 450\nvoid main() {}\n",
+		},
+		{
+			name:   "nested",
+			source: "#version 450\n#include \"common.glsl\"\nvoid main() {}\n",
+			want:   "#version 450\nconst float pi = 3.14159;\nconst float scale = 2.0;\nvoid main() {}\n",
+		},
+		{
+			name:   "angle brackets",
+			source: "  #include <lib/util.glsl>  \n",
+			want:   "float half(float x) { return x * 0.5; }\n",
+		},
+		{
+			name:   "included twice",
+			source: "#include \"math.glsl\"\n#include \"common.glsl\"\n",
+			want:   "const float pi = 3.14159;\nconst float pi = 3.14159;\nconst float scale = 2.0;\n",
+		},
+	} {
+		got, err := expandIncludes(test.source, resolve, nil)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestExpandIncludesErrors(t *testing.T) {
+	errMissing := errors.New("missing")
+	resolve := includeFiles(map[string]string{
+		"a.glsl":    "#include \"b.glsl\"\n",
+		"b.glsl":    "#include \"a.glsl\"\n",
+		"self.glsl": "#include <self.glsl>\n",
+	})
+	for _, test := range []struct {
+		name    string
+		source  string
+		resolve func(string) (string, error)
+		want    string
+	}{
+		{"cycle", "#include \"a.glsl\"\n", resolve, "circular include: a.glsl -> b.glsl -> a.glsl"},
+		{"self", "#include \"self.glsl\"\n", resolve, "circular include: self.glsl -> self.glsl"},
+		{"unquoted", "#include common.glsl\n", resolve, `malformed include directive "#include common.glsl"`},
+		{"mismatched", "#include \"common.glsl>\n", resolve, `malformed include directive "#include \"common.glsl>"`},
+		{"empty", "#include\n", resolve, `malformed include directive "#include"`},
+		{"resolver", "#include \"missing.glsl\"\n", func(string) (string, error) { return "", errMissing }, `include "missing.glsl": missing`},
+	} {
+		_, err := expandIncludes(test.source, test.resolve, nil)
+		if err == nil {
+			t.Errorf("%s: expected an error", test.name)
+			continue
+		}
+		if err.Error() != test.want {
+			t.Errorf("%s: got error %q, want %q", test.name, err, test.want)
+		}
+		if test.name == "resolver" && !errors.Is(err, errMissing) {
+			t.Errorf("%s: error %q does not wrap the resolver's error", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package RenderingDevice
+
+import (
+	"encoding/binary"
+
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+// DispatchIndirectArgs are the arguments read by [Instance.ComputeListDispatchIndirect].
+type DispatchIndirectArgs struct {
+	X, Y, Z uint32 // number of workgroups on each axis.
+}
+
+// DrawIndirectArgs are the arguments read by [Instance.DrawListDrawIndirect] for non-indexed draws.
+type DrawIndirectArgs struct {
+	VertexCount   uint32
+	InstanceCount uint32
+	FirstVertex   uint32
+	FirstInstance uint32
+}
+
+// DrawIndexedIndirectArgs are the arguments read by [Instance.DrawListDrawIndirect] for indexed draws.
+type DrawIndexedIndirectArgs struct {
+	IndexCount    uint32
+	InstanceCount uint32
+	FirstIndex    uint32
+	VertexOffset  int32
+	FirstInstance uint32
+}
+
+// StorageBufferFromDispatchArgs creates a storage buffer usable for indirect dispatches, holding
+// the given arguments one after the other. Compute shaders can then overwrite them on the GPU.
+func StorageBufferFromDispatchArgs(dev Instance, args ...DispatchIndirectArgs) RID.StorageBuffer {
+	return indirectBuffer(dev, args)
+}
+
+// StorageBufferFromDrawArgs creates a storage buffer usable for indirect draws, holding the given
+// arguments one after the other. Compute shaders can then overwrite them on the GPU.
+func StorageBufferFromDrawArgs(dev Instance, args ...DrawIndirectArgs) RID.StorageBuffer {
+	return indirectBuffer(dev, args)
+}
+
+// StorageBufferFromDrawIndexedArgs creates a storage buffer usable for indexed indirect draws,
+// holding the given arguments one after the other. Compute shaders can then overwrite them on the GPU.
+func StorageBufferFromDrawIndexedArgs(dev Instance, args ...DrawIndexedIndirectArgs) RID.StorageBuffer {
+	return indirectBuffer(dev, args)
+}
+
+// indirectBuffer packs the indirect arguments into a new storage buffer.
+func indirectBuffer[T DispatchIndirectArgs | DrawIndirectArgs | DrawIndexedIndirectArgs](dev Instance, args []T) RID.StorageBuffer {
+	data, err := binary.Append(nil, binary.LittleEndian, args)
+	if err != nil {
+		panic(err) // unreachable, the argument structs have a fixed size.
+	}
+	buffer := Expanded(dev).StorageBufferCreate(len(data), data, Rendering.StorageBufferUsageDispatchIndirect, 0)
+	stateOf(dev).sized(RID.Any(buffer), len(data))
+	return buffer
+}
@@ -0,0 +1,85 @@
+package RenderingDevice
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"graphics.gd/classdb/RDShaderSource"
+	"graphics.gd/classdb/RDUniform"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+// kernel is a compute shader used internally by the helpers in this package.
+type kernel struct {
+	shader   RID.Shader
+	pipeline RID.ComputePipeline
+}
+
+// kernel returns the compute pipeline for the given GLSL source, compiling it (as a
+// shader with the given name) on first use and caching it on the device.
+func (s *state) kernel(dev Instance, name, glsl string) (kernel, error) {
+	s.mutex.Lock()
+	k, ok := s.kernels[glsl]
+	s.mutex.Unlock()
+	if ok {
+		return k, nil
+	}
+	shader, err := compileCompute(dev, name, glsl)
+	if err != nil {
+		return kernel{}, err
+	}
+	pipeline := dev.ComputePipelineCreate(shader)
+	if !dev.ComputePipelineIsValid(pipeline) {
+		dev.FreeRidTracked(RID.Any(shader))
+		return kernel{}, fmt.Errorf("%s: failed to create compute pipeline", name)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if existing, ok := s.kernels[glsl]; ok {
+		dev.FreeRidTracked(RID.Any(pipeline))
+		dev.FreeRidTracked(RID.Any(shader))
+		return existing, nil
+	}
+	if s.kernels == nil {
+		s.kernels = make(map[string]kernel)
+	}
+	if s.tracked == nil {
+		s.tracked = make(map[RID.Any]struct{})
+	}
+	k = kernel{shader: shader, pipeline: pipeline}
+	s.kernels[glsl] = k
+	s.tracked[RID.Any(pipeline)] = struct{}{}
+	return k, nil
+}
+
+// compileCompute compiles the given GLSL compute shader source into a shader.
+func compileCompute(dev Instance, name, glsl string) (RID.Shader, error) {
+	source := RDShaderSource.New()
+	source.SetSourceCompute(glsl)
+	spirv := dev.ShaderCompileSpirvFromSource(source)
+	return ShaderCreate(dev, spirv, name)
+}
+
+// uniform returns a new uniform of the given type, binding the given ids.
+func uniform(kind Rendering.UniformType, binding int, ids ...RID.Any) RDUniform.Instance {
+	u := RDUniform.New()
+	u.SetUniformType(kind)
+	u.SetBinding(binding)
+	for _, id := range ids {
+		u.AddId(id)
+	}
+	return u
+}
+
+// pushConstant packs the given values into a push constant, padded to 16 bytes.
+func pushConstant(values ...uint32) []byte {
+	buf := make([]byte, 0, (len(values)*4+15)&^15)
+	for _, v := range values {
+		buf = binary.LittleEndian.AppendUint32(buf, v)
+	}
+	return buf[:cap(buf)]
+}
+
+// groups returns the number of workgroups of the given size needed to cover n invocations.
+func groups(n, size int) int { return (n + size - 1) / size }
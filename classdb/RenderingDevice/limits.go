@@ -0,0 +1,146 @@
+package RenderingDevice
+
+import (
+	"strings"
+
+	"graphics.gd/classdb/Rendering"
+)
+
+// Limits supported by [Instance.LimitGet] that are not exposed by the engine's API.
+const (
+	limitSubgroupSize       Rendering.Limit = 37
+	limitSubgroupMinSize    Rendering.Limit = 38
+	limitSubgroupMaxSize    Rendering.Limit = 39
+	limitSubgroupInShaders  Rendering.Limit = 40 // shader stage bits.
+	limitSubgroupOperations Rendering.Limit = 41 // subgroup operation bits.
+)
+
+const subgroupBasicBit = 1 // SUBGROUP_BASIC_BIT, required for any subgroup operation.
+
+// SubgroupSize returns the number of invocations in a subgroup (also known as a wave or warp)
+// on the device, typically 32 on NVIDIA and Intel hardware and 64 on AMD hardware, or zero if
+// the device does not report it.
+func (self Instance) SubgroupSize() int {
+	return self.LimitGet(limitSubgroupSize)
+}
+
+// SupportsSubgroupOps reports whether compute shaders on the device can use subgroup operations.
+func (self Instance) SupportsSubgroupOps() bool {
+	return Rendering.ShaderStage(self.LimitGet(limitSubgroupInShaders))&Rendering.ShaderStageComputeBit != 0 &&
+		self.LimitGet(limitSubgroupOperations)&subgroupBasicBit != 0
+}
+
+// softwareDevices are substrings of the names of well-known software rasterizers.
+var softwareDevices = []string{"llvmpipe", "lavapipe", "swiftshader", "warp"}
+
+// IsSoftwareDevice reports whether the device is a software rasterizer running on the CPU, such
+// as Lavapipe or SwiftShader in a CI environment, where expensive GPU work should be skipped.
+func (self Instance) IsSoftwareDevice() bool {
+	if videoAdapterType() == Rendering.DeviceTypeCpu {
+		return true
+	}
+	name := strings.ToLower(self.GetDeviceName())
+	for _, software := range softwareDevices {
+		if strings.Contains(name, software) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeviceFeatures reports which optional features are enabled on a device. The list of enabled
+// driver extensions is not exposed by the engine, so only those features that it reports on are
+// included.
+type DeviceFeatures struct {
+	BufferDeviceAddress bool                  // buffers can be created with [Rendering.BufferCreationDeviceAddressBit].
+	SubgroupOperations  bool                  // compute shaders can use subgroup operations, see [Instance.SupportsSubgroupOps].
+	SubgroupStages      Rendering.ShaderStage // bits of the shader stages that support subgroup operations.
+}
+
+// Features returns the optional features that are enabled on the device.
+func (self Instance) Features() DeviceFeatures {
+	return DeviceFeatures{
+		BufferDeviceAddress: self.HasFeature(Rendering.SupportsBufferDeviceAddress),
+		SubgroupOperations:  self.SupportsSubgroupOps(),
+		SubgroupStages:      Rendering.ShaderStage(self.LimitGet(limitSubgroupInShaders)),
+	}
+}
+
+// MaxBoundUniformSets returns the maximum number of uniform sets (descriptor sets) that can be
+// bound at once, so uniform sets can only use indices from zero to one less than this.
+func (self Instance) MaxBoundUniformSets() int {
+	return self.LimitGet(Rendering.LimitMaxBoundUniformSets)
+}
+
+// Capabilities are the optional features and limits of a device, see [Instance.Capabilities].
+type Capabilities struct {
+	DeviceFeatures
+
+	MaxBoundUniformSets             int
+	MaxFramebufferColorAttachments  int
+	MaxFramebufferSize              [2]int
+	MaxTextureSize1D                int
+	MaxTextureSize2D                int
+	MaxTextureSize3D                int
+	MaxTextureSizeCube              int
+	MaxTextureArrayLayers           int
+	MaxPushConstantSize             int
+	MaxUniformBufferSize            int
+	MinUniformBufferOffsetAlignment int
+	MaxVertexInputAttributes        int
+	MaxVertexInputBindings          int
+	MaxComputeSharedMemorySize      int
+	MaxComputeWorkgroupInvocations  int
+	MaxComputeWorkgroupSize         [3]int
+	MaxComputeWorkgroupCount        [3]int
+	MaxViewportDimensions           [2]int
+	SubgroupSize                    int
+}
+
+// Capabilities returns the optional features and limits of the device. They are queried once
+// and then cached for the lifetime of the device, rather than calling into the engine for each
+// [Instance.HasFeature] and [Instance.LimitGet].
+func (self Instance) Capabilities() Capabilities {
+	s := stateOf(self)
+	s.mutex.Lock()
+	caps := s.caps
+	s.mutex.Unlock()
+	if caps != nil {
+		return *caps
+	}
+	limit := self.LimitGet
+	caps = &Capabilities{
+		DeviceFeatures:                  self.Features(),
+		MaxBoundUniformSets:             limit(Rendering.LimitMaxBoundUniformSets),
+		MaxFramebufferColorAttachments:  limit(Rendering.LimitMaxFramebufferColorAttachments),
+		MaxFramebufferSize:              [2]int{limit(Rendering.LimitMaxFramebufferWidth), limit(Rendering.LimitMaxFramebufferHeight)},
+		MaxTextureSize1D:                limit(Rendering.LimitMaxTextureSize1d),
+		MaxTextureSize2D:                limit(Rendering.LimitMaxTextureSize2d),
+		MaxTextureSize3D:                limit(Rendering.LimitMaxTextureSize3d),
+		MaxTextureSizeCube:              limit(Rendering.LimitMaxTextureSizeCube),
+		MaxTextureArrayLayers:           limit(Rendering.LimitMaxTextureArrayLayers),
+		MaxPushConstantSize:             limit(Rendering.LimitMaxPushConstantSize),
+		MaxUniformBufferSize:            limit(Rendering.LimitMaxUniformBufferSize),
+		MinUniformBufferOffsetAlignment: limit(Rendering.LimitMinUniformBufferOffsetAlignment),
+		MaxVertexInputAttributes:        limit(Rendering.LimitMaxVertexInputAttributes),
+		MaxVertexInputBindings:          limit(Rendering.LimitMaxVertexInputBindings),
+		MaxComputeSharedMemorySize:      limit(Rendering.LimitMaxComputeSharedMemorySize),
+		MaxComputeWorkgroupInvocations:  limit(Rendering.LimitMaxComputeWorkgroupInvocations),
+		MaxComputeWorkgroupSize: [3]int{
+			limit(Rendering.LimitMaxComputeWorkgroupSizeX),
+			limit(Rendering.LimitMaxComputeWorkgroupSizeY),
+			limit(Rendering.LimitMaxComputeWorkgroupSizeZ),
+		},
+		MaxComputeWorkgroupCount: [3]int{
+			limit(Rendering.LimitMaxComputeWorkgroupCountX),
+			limit(Rendering.LimitMaxComputeWorkgroupCountY),
+			limit(Rendering.LimitMaxComputeWorkgroupCountZ),
+		},
+		MaxViewportDimensions: [2]int{limit(Rendering.LimitMaxViewportDimensionsX), limit(Rendering.LimitMaxViewportDimensionsY)},
+		SubgroupSize:          self.SubgroupSize(),
+	}
+	s.mutex.Lock()
+	s.caps = caps
+	s.mutex.Unlock()
+	return *caps
+}
@@ -0,0 +1,50 @@
+package RenderingDevice
+
+import (
+	"encoding/binary"
+	"math"
+
+	"graphics.gd/variant/Projection"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/Transform3D"
+)
+
+// UniformBufferFromTransform creates a uniform buffer holding the transform as a std140 mat4, so
+// that it can be declared as uniform Transform { mat4 transform; } in a shader. The basis axes
+// become the first three columns (each padded with a zero) and the origin becomes the last column
+// (padded with a one).
+func UniformBufferFromTransform(dev Instance, transform Transform3D.BasisOrigin) RID.UniformBuffer {
+	b, o := transform.Basis, transform.Origin
+	return uniformMat4(dev, [16]float32{
+		float32(b.X.X), float32(b.X.Y), float32(b.X.Z), 0,
+		float32(b.Y.X), float32(b.Y.Y), float32(b.Y.Z), 0,
+		float32(b.Z.X), float32(b.Z.Y), float32(b.Z.Z), 0,
+		float32(o.X), float32(o.Y), float32(o.Z), 1,
+	})
+}
+
+// UniformBufferFromProjection creates a uniform buffer holding the projection as a std140 mat4,
+// so that it can be declared as uniform Projection { mat4 projection; } in a shader.
+func UniformBufferFromProjection(dev Instance, projection Projection.XYZW) RID.UniformBuffer {
+	var m [16]float32
+	for i, column := range [4]struct{ X, Y, Z, W float32 }{
+		{float32(projection.X.X), float32(projection.X.Y), float32(projection.X.Z), float32(projection.X.W)},
+		{float32(projection.Y.X), float32(projection.Y.Y), float32(projection.Y.Z), float32(projection.Y.W)},
+		{float32(projection.Z.X), float32(projection.Z.Y), float32(projection.Z.Z), float32(projection.Z.W)},
+		{float32(projection.W.X), float32(projection.W.Y), float32(projection.W.Z), float32(projection.W.W)},
+	} {
+		m[i*4+0], m[i*4+1], m[i*4+2], m[i*4+3] = column.X, column.Y, column.Z, column.W
+	}
+	return uniformMat4(dev, m)
+}
+
+// uniformMat4 creates a uniform buffer holding the column-major matrix.
+func uniformMat4(dev Instance, m [16]float32) RID.UniformBuffer {
+	data := make([]byte, 0, len(m)*4)
+	for _, v := range m {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v))
+	}
+	buffer := Expanded(dev).UniformBufferCreate(len(data), data, 0)
+	stateOf(dev).sized(RID.Any(buffer), len(data))
+	return buffer
+}
@@ -0,0 +1,63 @@
+package RenderingDevice
+
+import (
+	"errors"
+	"fmt"
+
+	"graphics.gd/classdb/RDTextureFormat"
+	"graphics.gd/classdb/RDTextureView"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/Vector2i"
+)
+
+// CreateMSAATarget creates a multisampled color attachment of the given size and format, together
+// with a single-sampled texture of the same size and format that it can be resolved into (see
+// [ResolveMSAATarget]), which can then be sampled or copied from. An error is returned if the
+// device does not support the format for color attachments, or does not support the requested
+// sample count for it.
+func CreateMSAATarget(dev Instance, size Vector2i.XY, format Rendering.DataFormat, samples Rendering.TextureSamples) (msaa, resolve RID.Texture, err error) {
+	if size.X <= 0 || size.Y <= 0 {
+		return 0, 0, fmt.Errorf("invalid texture size %v", size)
+	}
+	if samples == Rendering.TextureSamples1 {
+		return 0, 0, errors.New("MSAA targets require more than one sample")
+	}
+	if !dev.TextureIsFormatSupportedForUsage(format, Rendering.TextureUsageColorAttachmentBit) {
+		return 0, 0, fmt.Errorf("format %v cannot be used as a color attachment", format)
+	}
+	tf := RDTextureFormat.New()
+	tf.SetTextureType(Rendering.TextureType2d)
+	tf.SetFormat(format)
+	tf.SetWidth(int(size.X))
+	tf.SetHeight(int(size.Y))
+	tf.SetSamples(samples)
+	tf.SetUsageBits(Rendering.TextureUsageColorAttachmentBit | Rendering.TextureUsageCanCopyFromBit)
+	msaa = dev.TextureCreate(tf, RDTextureView.New())
+	if msaa == RID.Texture(0) {
+		return 0, 0, fmt.Errorf("failed to create multisampled %vx%v texture", size.X, size.Y)
+	}
+	if actual := dev.TextureGetFormat(msaa).Samples(); actual != samples {
+		dev.FreeRidTracked(RID.Any(msaa))
+		return 0, 0, fmt.Errorf("sample count %v is not supported for format %v", samples, format)
+	}
+	tf = RDTextureFormat.New()
+	tf.SetTextureType(Rendering.TextureType2d)
+	tf.SetFormat(format)
+	tf.SetWidth(int(size.X))
+	tf.SetHeight(int(size.Y))
+	tf.SetUsageBits(Rendering.TextureUsageColorAttachmentBit | Rendering.TextureUsageSamplingBit |
+		Rendering.TextureUsageCanCopyFromBit | Rendering.TextureUsageCanCopyToBit)
+	resolve = dev.TextureCreate(tf, RDTextureView.New())
+	if resolve == RID.Texture(0) {
+		dev.FreeRidTracked(RID.Any(msaa))
+		return 0, 0, fmt.Errorf("failed to create %vx%v resolve texture", size.X, size.Y)
+	}
+	return msaa, resolve, nil
+}
+
+// ResolveMSAATarget resolves the multisampled texture into the resolve texture, as created by
+// [CreateMSAATarget].
+func ResolveMSAATarget(dev Instance, msaa, resolve RID.Texture) error {
+	return dev.TextureResolveMultisample(msaa, resolve)
+}
@@ -0,0 +1,94 @@
+package RenderingDevice
+
+import (
+	"runtime"
+
+	"graphics.gd/classdb/RDTextureFormat"
+	"graphics.gd/classdb/RDTextureView"
+	"graphics.gd/variant/RID"
+)
+
+// OwnedRID is a resource created on a device, that is freed when [OwnedRID.Free] is called, or
+// else after it is garbage collected, so that forgetting to free it does not leak GPU memory.
+//
+// The garbage collector runs finalizers on a separate goroutine, so resources that were garbage
+// collected are not freed immediately, instead they are freed on the device's thread. For the
+// main rendering device, the free is scheduled on the render thread (see
+// RenderingServer.CallOnRenderThread). For local devices, which have no thread of their own, they
+// are queued and freed by the next call to [Instance.FreeCollected], [Instance.SubmitTracked] or
+// any of the Owned constructors of this package.
+type OwnedRID[T ~uint64] struct {
+	dev Instance
+	rid T
+}
+
+// Own takes ownership of a resource created on the device, see [OwnedRID].
+func Own[T ~uint64](dev Instance, rid T) *OwnedRID[T] {
+	s := stateOf(dev)
+	s.collect(dev)
+	owned := &OwnedRID[T]{dev: dev, rid: rid}
+	if rid == 0 {
+		return owned
+	}
+	if isMainDevice(dev) {
+		runtime.SetFinalizer(owned, func(owned *OwnedRID[T]) {
+			rid := RID.Any(owned.rid)
+			callOnRenderThread(func() { dev.FreeRid(rid) })
+		})
+		return owned
+	}
+	runtime.SetFinalizer(owned, func(owned *OwnedRID[T]) {
+		s.mutex.Lock()
+		s.garbage = append(s.garbage, RID.Any(owned.rid))
+		s.mutex.Unlock()
+	})
+	return owned
+}
+
+// RID returns the resource, or zero if it has been freed.
+func (owned *OwnedRID[T]) RID() T { return owned.rid }
+
+// Free the resource, it must no longer be used afterwards. Freeing it more than once is a no-op.
+func (owned *OwnedRID[T]) Free() {
+	if owned.rid == 0 {
+		return
+	}
+	runtime.SetFinalizer(owned, nil)
+	owned.dev.FreeRidTracked(RID.Any(owned.rid))
+	owned.rid = 0
+}
+
+// Close is an alias for [OwnedRID.Free], so that owned resources satisfy [io.Closer].
+func (owned *OwnedRID[T]) Close() error {
+	owned.Free()
+	return nil
+}
+
+// TextureCreateOwned is like [Instance.TextureCreate] but the texture is an [OwnedRID].
+func TextureCreateOwned(dev Instance, format RDTextureFormat.Instance, view RDTextureView.Instance) *OwnedRID[RID.Texture] {
+	return Own(dev, dev.TextureCreate(format, view))
+}
+
+// StorageBufferCreateOwned is like [Instance.StorageBufferCreate] but the buffer is an [OwnedRID].
+func StorageBufferCreateOwned(dev Instance, size_bytes int) *OwnedRID[RID.StorageBuffer] {
+	buffer := dev.StorageBufferCreate(size_bytes)
+	stateOf(dev).sized(RID.Any(buffer), size_bytes)
+	return Own(dev, buffer)
+}
+
+// FreeCollected frees each [OwnedRID] of the local device that was garbage collected without being
+// freed. Those of the main rendering device are freed without having to call this.
+func (self Instance) FreeCollected() {
+	stateOf(self).collect(self)
+}
+
+// collect frees the garbage collected resources of the device.
+func (s *state) collect(dev Instance) {
+	s.mutex.Lock()
+	garbage := s.garbage
+	s.garbage = nil
+	s.mutex.Unlock()
+	for _, rid := range garbage {
+		dev.FreeRidTracked(rid)
+	}
+}
@@ -0,0 +1,50 @@
+package RenderingDevice
+
+import "graphics.gd/variant/RID"
+
+// PingPong holds a pair of resources (such as textures or storage buffers) for iterative compute
+// algorithms, where each pass reads from the current resource and writes to the next one, before
+// the two are swapped for the following pass.
+type PingPong[T ~uint64] struct {
+	dev       Instance
+	resources [2]T
+	current   int
+}
+
+// NewPingPong returns a [PingPong] that starts with current as the current resource.
+func NewPingPong[T ~uint64](dev Instance, current, next T) *PingPong[T] {
+	return &PingPong[T]{dev: dev, resources: [2]T{current, next}}
+}
+
+// Current returns the resource that the next pass reads from, after the last pass, it holds the result.
+func (p *PingPong[T]) Current() T { return p.resources[p.current] }
+
+// Next returns the resource that the next pass writes to.
+func (p *PingPong[T]) Next() T { return p.resources[1-p.current] }
+
+// Swap exchanges the current and next resources.
+func (p *PingPong[T]) Swap() { p.current = 1 - p.current }
+
+// RunIterations records n passes into a single compute list, calling pass with the compute list
+// and the resources to read from and write to, with a barrier between each pass, so that each
+// pass sees the results of the previous one. The resources are swapped after each pass.
+func (p *PingPong[T]) RunIterations(n int, pass func(compute_list int, current, next T)) {
+	if n <= 0 {
+		return
+	}
+	list := p.dev.ComputeListBegin()
+	for i := range n {
+		if i > 0 {
+			p.dev.ComputeListAddBarrier(list)
+		}
+		pass(list, p.Current(), p.Next())
+		p.Swap()
+	}
+	p.dev.ComputeListEnd()
+}
+
+// Free frees both resources.
+func (p *PingPong[T]) Free() {
+	p.dev.FreeRidTracked(RID.Any(p.resources[0]))
+	p.dev.FreeRidTracked(RID.Any(p.resources[1]))
+}
@@ -0,0 +1,226 @@
+package RenderingDevice
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"graphics.gd/classdb/RDPipelineColorBlendState"
+	"graphics.gd/classdb/RDPipelineColorBlendStateAttachment"
+	"graphics.gd/classdb/RDPipelineDepthStencilState"
+	"graphics.gd/classdb/RDPipelineMultisampleState"
+	"graphics.gd/classdb/RDPipelineRasterizationState"
+	"graphics.gd/classdb/RDPipelineSpecializationConstant"
+	"graphics.gd/classdb/Rendering"
+	gd "graphics.gd/internal"
+	"graphics.gd/internal/gdclass"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/RID"
+)
+
+// RenderPipelineBatch creates render pipelines that share the same fixed-function state and
+// only differ by shader, framebuffer format and vertex format, such as the per-material
+// variants of a pipeline compiled during loading. Set the shared state once, then call
+// [RenderPipelineBatch.Create] for each variant.
+type RenderPipelineBatch struct {
+	Primitive     Rendering.RenderPrimitive
+	Rasterization RDPipelineRasterizationState.Instance
+	Multisample   RDPipelineMultisampleState.Instance
+	DepthStencil  RDPipelineDepthStencilState.Instance
+	ColorBlend    RDPipelineColorBlendState.Instance
+	DynamicState  Rendering.PipelineDynamicStateFlags
+
+	dev       Instance
+	constants Array.Contains[[1]gdclass.RDPipelineSpecializationConstant]
+}
+
+// NewRenderPipelineBatch returns a [RenderPipelineBatch] for the device, drawing triangles with
+// the default rasterization, multisample and depth stencil state and a single color attachment
+// without blending.
+func NewRenderPipelineBatch(dev Instance) *RenderPipelineBatch {
+	blend := RDPipelineColorBlendState.New()
+	blend.SetAttachments([]RDPipelineColorBlendStateAttachment.Instance{RDPipelineColorBlendStateAttachment.New()})
+	return &RenderPipelineBatch{
+		Primitive:     Rendering.RenderPrimitiveTriangles,
+		Rasterization: RDPipelineRasterizationState.New(),
+		Multisample:   RDPipelineMultisampleState.New(),
+		DepthStencil:  RDPipelineDepthStencilState.New(),
+		ColorBlend:    blend,
+		dev:           dev,
+		constants:     gd.ArrayFromSlice[Array.Contains[[1]gdclass.RDPipelineSpecializationConstant]]([]RDPipelineSpecializationConstant.Instance(nil)),
+	}
+}
+
+// Create a render pipeline for the given shader, framebuffer format and vertex format using
+// the shared state of the batch.
+func (batch *RenderPipelineBatch) Create(shader RID.Shader, framebuffer_format, vertex_format int) RID.RenderPipeline {
+	return RID.RenderPipeline(Advanced(batch.dev).RenderPipelineCreate(RID.Any(shader), int64(framebuffer_format), int64(vertex_format),
+		batch.Primitive, batch.Rasterization, batch.Multisample, batch.DepthStencil, batch.ColorBlend,
+		batch.DynamicState, 0, batch.constants))
+}
+
+// MultisampleState returns a multisample state for pipelines that render to framebuffers with
+// the given sample count, see [FramebufferSampleCount]. The sample count of a pipeline must match
+// the framebuffer that it draws to, or nothing is rendered.
+func MultisampleState(samples Rendering.TextureSamples) RDPipelineMultisampleState.Instance {
+	state := RDPipelineMultisampleState.New()
+	state.SetSampleCount(samples)
+	return state
+}
+
+// MultisampleStateDisabled returns a multisample state for pipelines that render to framebuffers
+// without multisampling.
+func MultisampleStateDisabled() RDPipelineMultisampleState.Instance {
+	return MultisampleState(Rendering.TextureSamples1)
+}
+
+// FramebufferSampleCount returns the number of samples per pixel of the framebuffer's attachments.
+func FramebufferSampleCount(dev Instance, framebuffer RID.Framebuffer) Rendering.TextureSamples {
+	return dev.FramebufferFormatGetTextureSamples(dev.FramebufferGetFormat(framebuffer))
+}
+
+// RenderPipelineCreateDefault creates a render pipeline for drawing the shader into the framebuffer
+// with the default state: opaque triangles without depth testing, a single color attachment and
+// the framebuffer's sample count. The shader must not have any vertex inputs, as vertices are
+// generated procedurally (such as from gl_VertexIndex) by such pipelines, use a
+// [RenderPipelineBatch] for pipelines that read vertex buffers.
+func RenderPipelineCreateDefault(dev Instance, shader RID.Shader, framebuffer RID.Framebuffer) (RID.RenderPipeline, error) {
+	if _, ok := stateOf(dev).reflect(shader); ok && !dev.ShaderIsGraphics(shader) {
+		return RID.RenderPipeline(0), fmt.Errorf("shader %v is not a graphics shader", shader)
+	}
+	if mask := dev.ShaderGetVertexInputAttributeMask(shader); mask != 0 {
+		return RID.RenderPipeline(0), fmt.Errorf("shader %v has vertex inputs (mask %#x) but no vertex format was given", shader, mask)
+	}
+	format := dev.FramebufferGetFormat(framebuffer)
+	if format < 0 {
+		return RID.RenderPipeline(0), fmt.Errorf("invalid framebuffer %v", framebuffer)
+	}
+	batch := NewRenderPipelineBatch(dev)
+	batch.Multisample = MultisampleState(dev.FramebufferFormatGetTextureSamples(format))
+	pipeline := batch.Create(shader, format, -1)
+	if !dev.RenderPipelineIsValid(pipeline) {
+		return RID.RenderPipeline(0), fmt.Errorf("failed to create render pipeline for shader %v", shader)
+	}
+	return pipeline, nil
+}
+
+// snapshot returns a copy of the batch for the device, with copies of its state objects, so that
+// the copy is unaffected by later changes to the batch.
+func (batch *RenderPipelineBatch) snapshot(dev Instance) *RenderPipelineBatch {
+	blend := cloneState(batch.ColorBlend, RDPipelineColorBlendState.New)
+	if blend != batch.ColorBlend {
+		attachments := blend.Attachments()
+		for i, attachment := range attachments {
+			attachments[i] = cloneState(attachment, RDPipelineColorBlendStateAttachment.New)
+		}
+		blend.SetAttachments(attachments)
+	}
+	return &RenderPipelineBatch{
+		Primitive:     batch.Primitive,
+		Rasterization: cloneState(batch.Rasterization, RDPipelineRasterizationState.New),
+		Multisample:   cloneState(batch.Multisample, RDPipelineMultisampleState.New),
+		DepthStencil:  cloneState(batch.DepthStencil, RDPipelineDepthStencilState.New),
+		ColorBlend:    blend,
+		DynamicState:  batch.DynamicState,
+		dev:           dev,
+		constants:     gd.ArrayFromSlice[Array.Contains[[1]gdclass.RDPipelineSpecializationConstant]]([]RDPipelineSpecializationConstant.Instance(nil)),
+	}
+}
+
+// cloneState returns a new pipeline state object made with create, with each property of src
+// (that is, each value with both a getter and a Set method) copied over. Nil states are returned
+// as is.
+func cloneState[T any](src T, create func() T) T {
+	from := reflect.ValueOf(src)
+	if from.IsZero() {
+		return src
+	}
+	dst := create()
+	to := reflect.ValueOf(dst)
+	rtype := from.Type()
+	for i := range rtype.NumMethod() {
+		set := rtype.Method(i)
+		name, ok := strings.CutPrefix(set.Name, "Set")
+		if !ok || set.Type.NumIn() != 2 || set.Type.NumOut() != 0 {
+			continue
+		}
+		get, ok := rtype.MethodByName(name)
+		if !ok || get.Type.NumIn() != 1 || get.Type.NumOut() != 1 || get.Type.Out(0) != set.Type.In(1) {
+			continue
+		}
+		to.Method(i).Call(from.Method(get.Index).Call(nil))
+	}
+	return dst
+}
+
+// PendingRenderPipeline is a render pipeline that is being created by [RenderPipelineCreateAsync].
+// Until it is ready, draws should either be skipped or use the fallback pipeline instead, as done
+// by [PendingRenderPipeline.Bind].
+type PendingRenderPipeline struct {
+	pipeline atomic.Uint64
+	fallback RID.RenderPipeline
+}
+
+// Pipeline returns the render pipeline and true once it has been created successfully. Until then
+// (or if creation failed), the fallback pipeline is returned along with false.
+func (pending *PendingRenderPipeline) Pipeline() (RID.RenderPipeline, bool) {
+	if pipeline := RID.RenderPipeline(pending.pipeline.Load()); pipeline != RID.RenderPipeline(0) {
+		return pipeline, true
+	}
+	return pending.fallback, false
+}
+
+// Bind binds the render pipeline to the draw list if it is ready, or else the fallback pipeline.
+// Returns false, without binding anything, if neither is available, in which case the draw should
+// be skipped.
+func (pending *PendingRenderPipeline) Bind(dev Instance, draw_list int) bool {
+	pipeline, _ := pending.Pipeline()
+	if pipeline == RID.RenderPipeline(0) {
+		return false
+	}
+	dev.DrawListBindRenderPipeline(draw_list, pipeline)
+	return true
+}
+
+// RenderPipelineCreateAsync creates a render pipeline for the given shader, framebuffer format and
+// vertex format using the shared state of the batch (or the default state of
+// [NewRenderPipelineBatch] if batch is nil), without creating it within the caller's rendering
+// code, so that compiling the pipeline mid-gameplay doesn't hold up the frame being recorded. The
+// returned [PendingRenderPipeline] provides the fallback pipeline (which may be zero, to skip
+// draws) until the pipeline is ready. The batch's state is copied, so it may be modified
+// afterwards.
+//
+// For the main rendering device, the pipeline is created on the render thread (see
+// RenderingServer.CallOnRenderThread), which is the calling thread itself (so the pipeline is
+// created before returning) unless the project renders on a separate thread. For local devices,
+// the pipeline is created by the next call to [Instance.SubmitTracked], on the caller's goroutine.
+// done, if not nil, is called on that same thread once the pipeline has been created, or with an
+// error if it could not be, so it may use the device but must not block.
+func RenderPipelineCreateAsync(dev Instance, batch *RenderPipelineBatch, shader RID.Shader, framebuffer_format, vertex_format int, fallback RID.RenderPipeline, done func(RID.RenderPipeline, error)) *PendingRenderPipeline {
+	if batch == nil {
+		batch = NewRenderPipelineBatch(dev)
+	}
+	shared := batch.snapshot(dev)
+	pending := &PendingRenderPipeline{fallback: fallback}
+	create := func() {
+		pipeline := shared.Create(shader, framebuffer_format, vertex_format)
+		var err error
+		if !dev.RenderPipelineIsValid(pipeline) {
+			pipeline, err = RID.RenderPipeline(0), fmt.Errorf("failed to create render pipeline for shader %v", shader)
+		}
+		pending.pipeline.Store(uint64(pipeline))
+		if done != nil {
+			done(pipeline, err)
+		}
+	}
+	if isMainDevice(dev) {
+		callOnRenderThread(create)
+		return pending
+	}
+	s := stateOf(dev)
+	s.mutex.Lock()
+	s.queued = append(s.queued, create)
+	s.mutex.Unlock()
+	return pending
+}
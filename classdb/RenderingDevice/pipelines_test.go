@@ -0,0 +1,39 @@
+package RenderingDevice
+
+import "testing"
+
+// fakeState mimics the getters and setters of the generated pipeline state classes.
+type fakeState struct{ p *fakeFields }
+
+type fakeFields struct {
+	width  float64
+	wire   bool
+	ignore int
+}
+
+func (s fakeState) LineWidth() float64     { return s.p.width }
+func (s fakeState) SetLineWidth(v float64) { s.p.width = v }
+func (s fakeState) Wireframe() bool        { return s.p.wire }
+func (s fakeState) SetWireframe(v bool)    { s.p.wire = v }
+func (s fakeState) SetWithoutGetter(v int) { s.p.ignore = v }
+func (s fakeState) Mismatched() string     { return "" }
+func (s fakeState) SetMismatched(v int)    { s.p.ignore = v }
+func newFakeState() fakeState              { return fakeState{new(fakeFields)} }
+
+func TestCloneState(t *testing.T) {
+	src := fakeState{&fakeFields{width: 2.5, wire: true, ignore: 7}}
+	dst := cloneState(src, newFakeState)
+	if dst.p == src.p {
+		t.Fatal("expected a new state")
+	}
+	if *dst.p != (fakeFields{width: 2.5, wire: true}) {
+		t.Fatalf("got %+v, want the properties with getters copied", *dst.p)
+	}
+	src.SetLineWidth(1)
+	if dst.LineWidth() != 2.5 {
+		t.Fatal("expected the copy to be unaffected by changes to the original")
+	}
+	if nilState := cloneState(fakeState{}, newFakeState); nilState.p != nil {
+		t.Fatal("expected a nil state to be returned as is")
+	}
+}
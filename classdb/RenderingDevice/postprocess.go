@@ -0,0 +1,128 @@
+package RenderingDevice
+
+import (
+	"fmt"
+
+	"graphics.gd/classdb/RDShaderSource"
+	"graphics.gd/classdb/RDUniform"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+const fullscreenTriangleGLSL = `#version 450
+layout(location = 0) out vec2 uv;
+void main() {
+	uv = vec2((gl_VertexIndex << 1) & 2, gl_VertexIndex & 2);
+	gl_Position = vec4(uv * 2.0 - 1.0, 0.0, 1.0);
+}
+`
+
+// PostProcess renders textures through a fragment shader into textures of the same size, such as
+// for color grading or blurring the output of a render pass, see [NewPostProcess].
+type PostProcess struct {
+	dev       Instance
+	format    Rendering.DataFormat
+	shader    RID.Shader
+	pool      *TexturePool
+	pipelines map[int]RID.RenderPipeline      // by framebuffer format.
+	targets   map[RID.Texture]RID.Framebuffer // by output texture.
+}
+
+// NewPostProcess compiles the fragment shader for post-processing into textures of the given
+// format. The shader is drawn over a fullscreen triangle, so it should be declared as follows:
+//
+//	#version 450
+//	layout(location = 0) in vec2 uv;
+//	layout(set = 0, binding = 0) uniform sampler2D source;
+//	layout(location = 0) out vec4 color;
+//	void main() {
+//		color = texture(source, uv);
+//	}
+func NewPostProcess(dev Instance, fragment_glsl string, format Rendering.DataFormat) (*PostProcess, error) {
+	const usage = Rendering.TextureUsageColorAttachmentBit | Rendering.TextureUsageSamplingBit
+	if !dev.TextureIsFormatSupportedForUsage(format, usage) {
+		return nil, fmt.Errorf("format %v cannot be both rendered to and sampled", format)
+	}
+	source := RDShaderSource.New()
+	source.SetSourceVertex(fullscreenTriangleGLSL)
+	source.SetSourceFragment(fragment_glsl)
+	shader, err := ShaderCreate(dev, dev.ShaderCompileSpirvFromSource(source), "post process")
+	if err != nil {
+		return nil, err
+	}
+	return &PostProcess{
+		dev:       dev,
+		format:    format,
+		shader:    shader,
+		pool:      NewTexturePool(dev),
+		pipelines: make(map[int]RID.RenderPipeline),
+		targets:   make(map[RID.Texture]RID.Framebuffer),
+	}, nil
+}
+
+// Apply renders the input texture, sampled with the given sampler, through the shader into an
+// output texture of the same size. Release the output with [PostProcess.Release] once it is no
+// longer needed, so that it can be reused by later calls.
+func (pp *PostProcess) Apply(input RID.Texture, input_sampler RID.Sampler) (output RID.Texture, err error) {
+	const usage = Rendering.TextureUsageColorAttachmentBit | Rendering.TextureUsageSamplingBit | Rendering.TextureUsageCanCopyFromBit
+	dev := pp.dev
+	output, err = pp.pool.Acquire(TextureSize(dev, input), pp.format, usage)
+	if err != nil {
+		return RID.Texture(0), err
+	}
+	framebuffer, ok := pp.targets[output]
+	if !ok || !dev.FramebufferIsValid(framebuffer) {
+		framebuffer, err = FramebufferCreateMRT(dev, []RID.Texture{output}, 0)
+		if err != nil {
+			pp.pool.Release(output)
+			return RID.Texture(0), err
+		}
+		pp.targets[output] = framebuffer
+	}
+	format := dev.FramebufferGetFormat(framebuffer)
+	pipeline, ok := pp.pipelines[format]
+	if !ok {
+		pipeline, err = RenderPipelineCreateDefault(dev, pp.shader, framebuffer)
+		if err != nil {
+			pp.pool.Release(output)
+			return RID.Texture(0), err
+		}
+		pp.pipelines[format] = pipeline
+	}
+	set := dev.UniformSetCreate([]RDUniform.Instance{
+		uniform(Rendering.UniformTypeSamplerWithTexture, 0, RID.Any(input_sampler), RID.Any(input)),
+	}, pp.shader, 0)
+	if set == RID.UniformSet(0) {
+		pp.pool.Release(output)
+		return RID.Texture(0), fmt.Errorf("failed to create uniform set for texture %v", input)
+	}
+	defer dev.FreeRidTracked(RID.Any(set))
+	list := dev.DrawListBegin(framebuffer)
+	dev.DrawListBindRenderPipeline(list, pipeline)
+	dev.DrawListBindUniformSet(list, set, 0)
+	Expanded(dev).DrawListDraw(list, false, 1, 3)
+	dev.DrawListEnd()
+	return output, nil
+}
+
+// Release returns an output texture of [PostProcess.Apply], so that it can be reused.
+func (pp *PostProcess) Release(output RID.Texture) {
+	pp.pool.Release(output)
+}
+
+// Free frees the shader and the released output textures of the post-process. Output textures
+// that have not been released remain valid, and must be freed by the caller.
+func (pp *PostProcess) Free() {
+	pp.pool.Trim()
+	for texture, framebuffer := range pp.targets {
+		if pp.dev.FramebufferIsValid(framebuffer) {
+			pp.dev.FreeRidTracked(RID.Any(framebuffer))
+		}
+		delete(pp.targets, texture)
+	}
+	for format, pipeline := range pp.pipelines {
+		pp.dev.FreeRidTracked(RID.Any(pipeline))
+		delete(pp.pipelines, format)
+	}
+	pp.dev.FreeRidTracked(RID.Any(pp.shader))
+}
@@ -0,0 +1,56 @@
+// Package rdtest provides helpers for testing compute shaders and other code that writes to
+// RenderingDevice buffers.
+package rdtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"graphics.gd/classdb/RenderingDevice"
+	"graphics.gd/variant/RID"
+)
+
+// maxMismatches is the number of mismatched values reported by a failed assertion.
+const maxMismatches = 10
+
+// AssertBufferEquals reads len(want) values of type T from the start of the buffer and fails the
+// test, listing the mismatched values, unless they are all equal to want.
+//
+// Note: This function blocks the GPU until the data is retrieved.
+func AssertBufferEquals[T comparable](t testing.TB, dev RenderingDevice.Instance, buffer RID.Buffer, want []T) {
+	t.Helper()
+	assertBuffer(t, dev, buffer, want, func(got, want T) bool { return got == want })
+}
+
+// AssertBufferNear is like [AssertBufferEquals], except that floating-point values only need to be
+// within epsilon of the wanted values.
+func AssertBufferNear[T ~float32 | ~float64](t testing.TB, dev RenderingDevice.Instance, buffer RID.Buffer, want []T, epsilon T) {
+	t.Helper()
+	assertBuffer(t, dev, buffer, want, func(got, want T) bool { return got-want <= epsilon && want-got <= epsilon })
+}
+
+func assertBuffer[T any](t testing.TB, dev RenderingDevice.Instance, buffer RID.Buffer, want []T, equal func(got, want T) bool) {
+	t.Helper()
+	got, err := RenderingDevice.BufferReadSlice[T](dev, buffer, 0, len(want))
+	if err != nil {
+		t.Fatalf("reading buffer %v: %v", buffer, err)
+	}
+	var diff strings.Builder
+	var mismatches int
+	for i := range want {
+		if equal(got[i], want[i]) {
+			continue
+		}
+		if mismatches < maxMismatches {
+			fmt.Fprintf(&diff, "\n\t[%d]: got %v, want %v", i, got[i], want[i])
+		}
+		mismatches++
+	}
+	if mismatches > maxMismatches {
+		fmt.Fprintf(&diff, "\n\t... and %d more", mismatches-maxMismatches)
+	}
+	if mismatches > 0 {
+		t.Errorf("buffer %v has %d mismatched values:%s", buffer, mismatches, diff.String())
+	}
+}
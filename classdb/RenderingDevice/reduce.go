@@ -0,0 +1,112 @@
+package RenderingDevice
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"graphics.gd/classdb/RDUniform"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+// ReduceOp is a reduction operation for [Reduce].
+type ReduceOp uint32
+
+const (
+	ReduceSum ReduceOp = iota
+	ReduceMin
+	ReduceMax
+	ReduceProduct
+)
+
+const reduceGLSL = `#version 450
+layout(local_size_x = 256, local_size_y = 1, local_size_z = 1) in;
+layout(set = 0, binding = 0, std430) restrict readonly buffer Input { float values[]; } src;
+layout(set = 0, binding = 1, std430) restrict writeonly buffer Output { float values[]; } dst;
+layout(push_constant, std430) uniform Params { uint count; uint op; uint pad0; uint pad1; } params;
+shared float partial[256];
+float identity() {
+	switch (params.op) {
+	case 1u: return 3.402823466e38;
+	case 2u: return -3.402823466e38;
+	case 3u: return 1.0;
+	default: return 0.0;
+	}
+}
+float combine(float a, float b) {
+	switch (params.op) {
+	case 1u: return min(a, b);
+	case 2u: return max(a, b);
+	case 3u: return a * b;
+	default: return a + b;
+	}
+}
+void main() {
+	uint i = gl_GlobalInvocationID.x;
+	uint local = gl_LocalInvocationID.x;
+	partial[local] = i < params.count ? src.values[i] : identity();
+	barrier();
+	for (uint stride = 128u; stride > 0u; stride >>= 1u) {
+		if (local < stride) {
+			partial[local] = combine(partial[local], partial[local + stride]);
+		}
+		barrier();
+	}
+	if (local == 0u) {
+		dst.values[gl_WorkGroupID.x] = partial[0];
+	}
+}
+`
+
+// Reduce combines the first count float32 values of the input storage buffer into a single
+// value with the given operation, using a tree reduction on the GPU.
+func Reduce(dev Instance, input RID.Buffer, count int, op ReduceOp) (float64, error) {
+	if count <= 0 {
+		return 0, errors.New("nothing to reduce")
+	}
+	if op > ReduceProduct {
+		return 0, errors.New("invalid reduce operation")
+	}
+	k, err := stateOf(dev).kernel(dev, "reduce", reduceGLSL)
+	if err != nil {
+		return 0, err
+	}
+	type pass struct {
+		count  int
+		groups int
+		set    RID.UniformSet
+	}
+	var passes []pass
+	var result = input
+	for n := count; n > 1; n = groups(n, 256) {
+		output := dev.StorageBufferCreate(groups(n, 256) * 4)
+		defer dev.FreeRidTracked(RID.Any(output))
+		set := dev.UniformSetCreate([]RDUniform.Instance{
+			uniform(Rendering.UniformTypeStorageBuffer, 0, RID.Any(result)),
+			uniform(Rendering.UniformTypeStorageBuffer, 1, RID.Any(output)),
+		}, k.shader, 0)
+		defer dev.FreeRidTracked(RID.Any(set))
+		passes = append(passes, pass{count: n, groups: groups(n, 256), set: set})
+		result = RID.Buffer(output)
+	}
+	if len(passes) > 0 {
+		list := dev.ComputeListBegin()
+		dev.ComputeListBindComputePipeline(list, k.pipeline)
+		for i, pass := range passes {
+			if i > 0 {
+				dev.ComputeListAddBarrier(list)
+			}
+			push := pushConstant(uint32(pass.count), uint32(op))
+			dev.ComputeListBindUniformSet(list, pass.set, 0)
+			dev.ComputeListSetPushConstant(list, push, len(push))
+			dev.ComputeListDispatch(list, pass.groups, 1, 1)
+		}
+		dev.ComputeListEnd()
+	}
+	data := Expanded(dev).BufferGetData(result, 0, 4)
+	if len(data) != 4 {
+		return 0, errors.New("failed to read back reduction")
+	}
+	return float64(math.Float32frombits(binary.LittleEndian.Uint32(data))), nil
+}
@@ -0,0 +1,377 @@
+package RenderingDevice
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"graphics.gd/classdb/RDShaderSPIRV"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+// ShaderReflection is the information about a shader that is decoded from its SPIR-V, see
+// [Instance.ShaderGetReflection].
+type ShaderReflection struct {
+	LocalSize        [3]int                // declared local workgroup size of a compute shader, zero if unknown.
+	Stages           Rendering.ShaderStage // bits of the stages present in the shader.
+	PushConstantSize int                   // size of the push constant block in bytes, zero if there is none.
+	Uniforms         []ShaderUniform       // ordered by set, then binding.
+}
+
+// ShaderUniform describes a uniform declared by a shader.
+type ShaderUniform struct {
+	Set     int
+	Binding int
+	Type    Rendering.UniformType
+	Length  int                   // number of elements for arrays, zero for runtime sized arrays, else 1.
+	Stages  Rendering.ShaderStage // bits of the stages that declare the uniform.
+	Size    int                   // size in bytes of a buffer block, excluding any runtime sized array.
+	Stride  int                   // stride in bytes of the runtime sized array ending a buffer block.
+}
+
+const (
+	spirvMagic             = 0x07230203
+	spirvExecutionModeSize = 17
+)
+
+// SPIR-V opcodes.
+const (
+	spirvOpExecutionMode    = 16
+	spirvOpTypeBool         = 20
+	spirvOpTypeInt          = 21
+	spirvOpTypeFloat        = 22
+	spirvOpTypeVector       = 23
+	spirvOpTypeMatrix       = 24
+	spirvOpTypeImage        = 25
+	spirvOpTypeSampler      = 26
+	spirvOpTypeSampledImage = 27
+	spirvOpTypeArray        = 28
+	spirvOpTypeRuntimeArray = 29
+	spirvOpTypeStruct       = 30
+	spirvOpTypePointer      = 32
+	spirvOpConstant         = 43
+	spirvOpVariable         = 59
+	spirvOpDecorate         = 71
+	spirvOpMemberDecorate   = 72
+)
+
+// SPIR-V decorations, storage classes and image dimensions.
+const (
+	spirvDecorationBufferBlock   = 3
+	spirvDecorationArrayStride   = 6
+	spirvDecorationMatrixStride  = 7
+	spirvDecorationBinding       = 33
+	spirvDecorationDescriptorSet = 34
+	spirvDecorationOffset        = 35
+
+	spirvStorageUniformConstant = 0
+	spirvStorageUniform         = 2
+	spirvStoragePushConstant    = 9
+	spirvStorageStorageBuffer   = 12
+
+	spirvDimBuffer      = 5
+	spirvDimSubpassData = 6
+)
+
+// spirvType is a type declaration of a SPIR-V module, with the operands that follow its result id.
+type spirvType struct {
+	op   uint32
+	args []uint32
+}
+
+// spirvModule holds the declarations of a SPIR-V module that are needed for reflection.
+type spirvModule struct {
+	types       map[uint32]spirvType
+	constants   map[uint32]uint32
+	decorations map[[2]uint32]uint32 // by target and decoration.
+	members     map[[3]uint32]uint32 // by struct, member and decoration.
+}
+
+// size returns the size in bytes of the type, as laid out by its decorations.
+func (m *spirvModule) size(id uint32) int {
+	t := m.types[id]
+	switch t.op {
+	case spirvOpTypeBool:
+		return 4
+	case spirvOpTypeInt, spirvOpTypeFloat:
+		return int(t.args[0]) / 8
+	case spirvOpTypeVector, spirvOpTypeMatrix:
+		return int(t.args[1]) * m.size(t.args[0])
+	case spirvOpTypeArray:
+		stride, ok := m.decorations[[2]uint32{id, spirvDecorationArrayStride}]
+		if !ok {
+			stride = uint32(m.size(t.args[0]))
+		}
+		return int(m.constants[t.args[1]] * stride)
+	case spirvOpTypeStruct:
+		var size int
+		for i, member := range t.args {
+			offset := m.members[[3]uint32{id, uint32(i), spirvDecorationOffset}]
+			msize := m.size(member)
+			if mt := m.types[member]; mt.op == spirvOpTypeMatrix {
+				if stride, ok := m.members[[3]uint32{id, uint32(i), spirvDecorationMatrixStride}]; ok {
+					msize = int(mt.args[1] * stride)
+				}
+			}
+			size = max(size, int(offset)+msize)
+		}
+		return size
+	default:
+		return 0
+	}
+}
+
+// uniformType returns the uniform type of a variable in the storage class pointing to the type.
+func (m *spirvModule) uniformType(storage, id uint32) (Rendering.UniformType, bool) {
+	t := m.types[id]
+	switch storage {
+	case spirvStorageStorageBuffer:
+		return Rendering.UniformTypeStorageBuffer, true
+	case spirvStorageUniform:
+		if _, ok := m.decorations[[2]uint32{id, spirvDecorationBufferBlock}]; ok {
+			return Rendering.UniformTypeStorageBuffer, true
+		}
+		return Rendering.UniformTypeUniformBuffer, true
+	}
+	switch t.op {
+	case spirvOpTypeSampler:
+		return Rendering.UniformTypeSampler, true
+	case spirvOpTypeSampledImage:
+		if image := m.types[t.args[0]]; image.op == spirvOpTypeImage && image.args[1] == spirvDimBuffer {
+			return Rendering.UniformTypeSamplerWithTextureBuffer, true
+		}
+		return Rendering.UniformTypeSamplerWithTexture, true
+	case spirvOpTypeImage:
+		if len(t.args) < 6 {
+			return 0, false
+		}
+		storage := t.args[5] == 2
+		switch {
+		case t.args[1] == spirvDimSubpassData:
+			return Rendering.UniformTypeInputAttachment, true
+		case t.args[1] == spirvDimBuffer && storage:
+			return Rendering.UniformTypeImageBuffer, true
+		case t.args[1] == spirvDimBuffer:
+			return Rendering.UniformTypeTextureBuffer, true
+		case storage:
+			return Rendering.UniformTypeImage, true
+		default:
+			return Rendering.UniformTypeTexture, true
+		}
+	default:
+		return 0, false
+	}
+}
+
+// reflectSPIRV decodes the reflection information from the given SPIR-V module.
+func reflectSPIRV(code []byte) (ShaderReflection, error) {
+	var info ShaderReflection
+	if len(code) < 20 || len(code)%4 != 0 {
+		return info, errors.New("invalid SPIR-V module")
+	}
+	order := binary.ByteOrder(binary.LittleEndian)
+	if order.Uint32(code) != spirvMagic {
+		order = binary.BigEndian
+		if order.Uint32(code) != spirvMagic {
+			return info, errors.New("invalid SPIR-V magic number")
+		}
+	}
+	words := make([]uint32, len(code)/4)
+	for i := range words {
+		words[i] = order.Uint32(code[i*4:])
+	}
+	module := spirvModule{
+		types:       make(map[uint32]spirvType),
+		constants:   make(map[uint32]uint32),
+		decorations: make(map[[2]uint32]uint32),
+		members:     make(map[[3]uint32]uint32),
+	}
+	var variables [][3]uint32 // pointer type, id and storage class.
+	for i := 5; i < len(words); {
+		count, opcode := int(words[i]>>16), words[i]&0xFFFF
+		if count == 0 || i+count > len(words) {
+			return info, errors.New("truncated SPIR-V instruction")
+		}
+		operands := words[i+1 : i+count]
+		switch opcode {
+		case spirvOpExecutionMode:
+			if len(operands) >= 5 && operands[1] == spirvExecutionModeSize {
+				info.LocalSize = [3]int{int(operands[2]), int(operands[3]), int(operands[4])}
+			}
+		case spirvOpTypeBool, spirvOpTypeInt, spirvOpTypeFloat, spirvOpTypeVector, spirvOpTypeMatrix,
+			spirvOpTypeImage, spirvOpTypeSampler, spirvOpTypeSampledImage, spirvOpTypeArray,
+			spirvOpTypeRuntimeArray, spirvOpTypeStruct, spirvOpTypePointer:
+			if len(operands) >= 1 {
+				module.types[operands[0]] = spirvType{op: opcode, args: operands[1:]}
+			}
+		case spirvOpConstant:
+			if len(operands) >= 3 {
+				module.constants[operands[1]] = operands[2]
+			}
+		case spirvOpVariable:
+			if len(operands) >= 3 {
+				variables = append(variables, [3]uint32{operands[0], operands[1], operands[2]})
+			}
+		case spirvOpDecorate:
+			if len(operands) >= 2 {
+				var value uint32
+				if len(operands) >= 3 {
+					value = operands[2]
+				}
+				module.decorations[[2]uint32{operands[0], operands[1]}] = value
+			}
+		case spirvOpMemberDecorate:
+			if len(operands) >= 4 {
+				module.members[[3]uint32{operands[0], operands[1], operands[2]}] = operands[3]
+			}
+		}
+		i += count
+	}
+	for _, variable := range variables {
+		pointer, id, storage := variable[0], variable[1], variable[2]
+		if t := module.types[pointer]; t.op != spirvOpTypePointer || len(t.args) < 2 {
+			continue
+		}
+		pointee := module.types[pointer].args[1]
+		if storage == spirvStoragePushConstant {
+			info.PushConstantSize = max(info.PushConstantSize, module.size(pointee))
+			continue
+		}
+		if storage != spirvStorageUniformConstant && storage != spirvStorageUniform && storage != spirvStorageStorageBuffer {
+			continue
+		}
+		set, ok1 := module.decorations[[2]uint32{id, spirvDecorationDescriptorSet}]
+		binding, ok2 := module.decorations[[2]uint32{id, spirvDecorationBinding}]
+		if !ok1 || !ok2 {
+			continue
+		}
+		length := 1
+		switch t := module.types[pointee]; t.op {
+		case spirvOpTypeArray:
+			length, pointee = int(module.constants[t.args[1]]), t.args[0]
+		case spirvOpTypeRuntimeArray:
+			length, pointee = 0, t.args[0]
+		}
+		kind, ok := module.uniformType(storage, pointee)
+		if !ok {
+			continue
+		}
+		uniform := ShaderUniform{Set: int(set), Binding: int(binding), Type: kind, Length: length}
+		if kind.IsBuffer() {
+			uniform.Size = module.size(pointee)
+			if t := module.types[pointee]; t.op == spirvOpTypeStruct && len(t.args) > 0 {
+				if last := t.args[len(t.args)-1]; module.types[last].op == spirvOpTypeRuntimeArray {
+					uniform.Stride = int(module.decorations[[2]uint32{last, spirvDecorationArrayStride}])
+				}
+			}
+		}
+		info.Uniforms = append(info.Uniforms, uniform)
+	}
+	return info, nil
+}
+
+// ShaderCreate creates a new shader from the given SPIR-V, returning any compilation errors
+// recorded in the SPIR-V as an error. Unlike [Instance.ShaderCreateFromSpirv], the shader's
+// SPIR-V is reflected so that it can be validated against by the other helpers in this
+// package, such as [Instance.ComputeListDispatchChecked].
+func ShaderCreate(dev Instance, spirv RDShaderSPIRV.Instance, name string) (RID.Shader, error) {
+	stages := []struct {
+		name  string
+		bit   Rendering.ShaderStage
+		error string
+		code  []byte
+	}{
+		{"vertex", Rendering.ShaderStageVertexBit, spirv.CompileErrorVertex(), spirv.BytecodeVertex()},
+		{"fragment", Rendering.ShaderStageFragmentBit, spirv.CompileErrorFragment(), spirv.BytecodeFragment()},
+		{"tesselation control", Rendering.ShaderStageTesselationControlBit, spirv.CompileErrorTesselationControl(), spirv.BytecodeTesselationControl()},
+		{"tesselation evaluation", Rendering.ShaderStageTesselationEvaluationBit, spirv.CompileErrorTesselationEvaluation(), spirv.BytecodeTesselationEvaluation()},
+		{"compute", Rendering.ShaderStageComputeBit, spirv.CompileErrorCompute(), spirv.BytecodeCompute()},
+	}
+	var info ShaderReflection
+	for _, stage := range stages {
+		if stage.error != "" {
+			return 0, fmt.Errorf("%s: %s shader: %s", name, stage.name, stage.error)
+		}
+		if len(stage.code) == 0 {
+			continue
+		}
+		info.Stages |= stage.bit
+		reflected, err := reflectSPIRV(stage.code)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %s shader: %w", name, stage.name, err)
+		}
+		if reflected.LocalSize != [3]int{} {
+			info.LocalSize = reflected.LocalSize
+		}
+		info.PushConstantSize = max(info.PushConstantSize, reflected.PushConstantSize)
+		for _, uniform := range reflected.Uniforms {
+			uniform.Stages = stage.bit
+			i, found := slices.BinarySearchFunc(info.Uniforms, uniform, compareUniforms)
+			if found {
+				info.Uniforms[i].Stages |= stage.bit
+				continue
+			}
+			info.Uniforms = slices.Insert(info.Uniforms, i, uniform)
+		}
+	}
+	shader := Expanded(dev).ShaderCreateFromSpirv(spirv, name)
+	if shader == 0 {
+		return 0, fmt.Errorf("%s: failed to create shader", name)
+	}
+	s := stateOf(dev)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.shaders == nil {
+		s.shaders = make(map[RID.Shader]ShaderReflection)
+	}
+	s.shaders[shader] = info
+	if s.tracked == nil {
+		s.tracked = make(map[RID.Any]struct{})
+	}
+	s.tracked[RID.Any(shader)] = struct{}{}
+	return shader, nil
+}
+
+// reflect returns the reflection information recorded for the shader by [ShaderCreate].
+func (s *state) reflect(shader RID.Shader) (ShaderReflection, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	info, ok := s.shaders[shader]
+	return info, ok
+}
+
+// compareUniforms orders uniforms by set, then binding.
+func compareUniforms(a, b ShaderUniform) int {
+	if a.Set != b.Set {
+		return a.Set - b.Set
+	}
+	return a.Binding - b.Binding
+}
+
+// ShaderGetReflection returns the information reflected from the SPIR-V of the shader, such as
+// the size of its push constant block and the uniforms that each of its uniform sets expects, so
+// that they can be validated before creating uniform sets. Returns false if the shader was not
+// created with [ShaderCreate].
+func (self Instance) ShaderGetReflection(shader RID.Shader) (ShaderReflection, bool) {
+	info, ok := stateOf(self).reflect(shader)
+	info.Uniforms = slices.Clone(info.Uniforms)
+	return info, ok
+}
+
+// ShaderIsCompute reports whether the shader, which must have been created with [ShaderCreate],
+// is a compute shader that can be used with [Instance.ComputePipelineCreate].
+func (self Instance) ShaderIsCompute(shader RID.Shader) bool {
+	info, ok := stateOf(self).reflect(shader)
+	return ok && info.Stages&Rendering.ShaderStageComputeBit != 0
+}
+
+// ShaderIsGraphics reports whether the shader, which must have been created with [ShaderCreate],
+// has graphics stages, so that it can be used with [Instance.RenderPipelineCreate].
+func (self Instance) ShaderIsGraphics(shader RID.Shader) bool {
+	const graphics = Rendering.ShaderStageVertexBit | Rendering.ShaderStageFragmentBit |
+		Rendering.ShaderStageTesselationControlBit | Rendering.ShaderStageTesselationEvaluationBit
+	info, ok := stateOf(self).reflect(shader)
+	return ok && info.Stages&graphics != 0
+}
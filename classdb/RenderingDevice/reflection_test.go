@@ -0,0 +1,173 @@
+package RenderingDevice
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"graphics.gd/classdb/Rendering"
+)
+
+// spirv assembles a SPIR-V module from the given instructions, each being the opcode followed by
+// its operands, after a header declaring bound as the id bound.
+func spirv(order binary.AppendByteOrder, bound uint32, instructions ...[]uint32) []byte {
+	code := order.AppendUint32(nil, spirvMagic)
+	code = order.AppendUint32(code, 0x00010000) // version 1.0
+	code = order.AppendUint32(code, 0)          // generator
+	code = order.AppendUint32(code, bound)
+	code = order.AppendUint32(code, 0) // schema
+	for _, inst := range instructions {
+		code = order.AppendUint32(code, uint32(len(inst))<<16|inst[0])
+		for _, operand := range inst[1:] {
+			code = order.AppendUint32(code, operand)
+		}
+	}
+	return code
+}
+
+// computeSPIRV assembles the declarations that glslang emits (without the function bodies and
+// debug names) when compiling:
+//
+//	#version 450
+//	layout(local_size_x = 8, local_size_y = 4, local_size_z = 1) in;
+//	layout(set = 0, binding = 0) uniform Globals { vec3 direction; float time; } globals;
+//	layout(set = 0, binding = 1, std430) buffer Data { vec4 header; float values[]; } data;
+//	layout(set = 1, binding = 0) uniform sampler2D textures[3];
+//	layout(set = 2, binding = 0, rgba8) uniform image2D image;
+//	layout(push_constant, std430) uniform Params { mat4 transform; uint count; } params;
+func computeSPIRV(order binary.AppendByteOrder) []byte {
+	const (
+		mainFn = iota + 1
+		voidType
+		floatType
+		vec3
+		vec4
+		uintType
+		mat4
+		three
+		Globals
+		globalsPtr
+		globals
+		values
+		Data
+		dataPtr
+		data
+		image2D
+		sampler2D
+		sampledImage
+		textureArray
+		texturesPtr
+		textures
+		imagePtr
+		image
+		Params
+		paramsPtr
+		params
+		bound
+	)
+	return spirv(order, bound,
+		[]uint32{17, 1},                        // OpCapability Shader
+		[]uint32{14, 0, 1},                     // OpMemoryModel Logical GLSL450
+		[]uint32{15, 5, mainFn, 0x6E69616D, 0}, // OpEntryPoint GLCompute %main "main"
+		[]uint32{spirvOpExecutionMode, mainFn, spirvExecutionModeSize, 8, 4, 1},
+		[]uint32{spirvOpMemberDecorate, Globals, 0, spirvDecorationOffset, 0},
+		[]uint32{spirvOpMemberDecorate, Globals, 1, spirvDecorationOffset, 12},
+		[]uint32{spirvOpDecorate, Globals, 2}, // Block
+		[]uint32{spirvOpDecorate, globals, spirvDecorationDescriptorSet, 0},
+		[]uint32{spirvOpDecorate, globals, spirvDecorationBinding, 0},
+		[]uint32{spirvOpDecorate, values, spirvDecorationArrayStride, 4},
+		[]uint32{spirvOpMemberDecorate, Data, 0, spirvDecorationOffset, 0},
+		[]uint32{spirvOpMemberDecorate, Data, 1, spirvDecorationOffset, 16},
+		[]uint32{spirvOpDecorate, Data, spirvDecorationBufferBlock},
+		[]uint32{spirvOpDecorate, data, spirvDecorationDescriptorSet, 0},
+		[]uint32{spirvOpDecorate, data, spirvDecorationBinding, 1},
+		[]uint32{spirvOpDecorate, textures, spirvDecorationDescriptorSet, 1},
+		[]uint32{spirvOpDecorate, textures, spirvDecorationBinding, 0},
+		[]uint32{spirvOpDecorate, image, spirvDecorationDescriptorSet, 2},
+		[]uint32{spirvOpDecorate, image, spirvDecorationBinding, 0},
+		[]uint32{spirvOpMemberDecorate, Params, 0, 5}, // ColMajor
+		[]uint32{spirvOpMemberDecorate, Params, 0, spirvDecorationOffset, 0},
+		[]uint32{spirvOpMemberDecorate, Params, 0, spirvDecorationMatrixStride, 16},
+		[]uint32{spirvOpMemberDecorate, Params, 1, spirvDecorationOffset, 64},
+		[]uint32{spirvOpDecorate, Params, 2}, // Block
+		[]uint32{19, voidType},               // OpTypeVoid
+		[]uint32{spirvOpTypeFloat, floatType, 32},
+		[]uint32{spirvOpTypeVector, vec3, floatType, 3},
+		[]uint32{spirvOpTypeVector, vec4, floatType, 4},
+		[]uint32{spirvOpTypeInt, uintType, 32, 0},
+		[]uint32{spirvOpTypeMatrix, mat4, vec4, 4},
+		[]uint32{spirvOpConstant, uintType, three, 3},
+		[]uint32{spirvOpTypeStruct, Globals, vec3, floatType},
+		[]uint32{spirvOpTypePointer, globalsPtr, spirvStorageUniform, Globals},
+		[]uint32{spirvOpVariable, globalsPtr, globals, spirvStorageUniform},
+		[]uint32{spirvOpTypeRuntimeArray, values, floatType},
+		[]uint32{spirvOpTypeStruct, Data, vec4, values},
+		[]uint32{spirvOpTypePointer, dataPtr, spirvStorageUniform, Data},
+		[]uint32{spirvOpVariable, dataPtr, data, spirvStorageUniform},
+		[]uint32{spirvOpTypeImage, image2D, floatType, 1, 0, 0, 0, 2, 4},   // 2D, storage, Rgba8
+		[]uint32{spirvOpTypeImage, sampler2D, floatType, 1, 0, 0, 0, 1, 0}, // 2D, sampled
+		[]uint32{spirvOpTypeSampledImage, sampledImage, sampler2D},
+		[]uint32{spirvOpTypeArray, textureArray, sampledImage, three},
+		[]uint32{spirvOpTypePointer, texturesPtr, spirvStorageUniformConstant, textureArray},
+		[]uint32{spirvOpVariable, texturesPtr, textures, spirvStorageUniformConstant},
+		[]uint32{spirvOpTypePointer, imagePtr, spirvStorageUniformConstant, image2D},
+		[]uint32{spirvOpVariable, imagePtr, image, spirvStorageUniformConstant},
+		[]uint32{spirvOpTypeStruct, Params, mat4, uintType},
+		[]uint32{spirvOpTypePointer, paramsPtr, spirvStoragePushConstant, Params},
+		[]uint32{spirvOpVariable, paramsPtr, params, spirvStoragePushConstant},
+	)
+}
+
+func TestReflectSPIRV(t *testing.T) {
+	compute := ShaderReflection{
+		LocalSize:        [3]int{8, 4, 1},
+		PushConstantSize: 68,
+		Uniforms: []ShaderUniform{
+			{Set: 0, Binding: 0, Type: Rendering.UniformTypeUniformBuffer, Length: 1, Size: 16},
+			{Set: 0, Binding: 1, Type: Rendering.UniformTypeStorageBuffer, Length: 1, Size: 16, Stride: 4},
+			{Set: 1, Binding: 0, Type: Rendering.UniformTypeSamplerWithTexture, Length: 3},
+			{Set: 2, Binding: 0, Type: Rendering.UniformTypeImage, Length: 1},
+		},
+	}
+	for _, test := range []struct {
+		name string
+		code []byte
+		want ShaderReflection
+	}{
+		{"compute", computeSPIRV(binary.LittleEndian), compute},
+		{"big endian", computeSPIRV(binary.BigEndian), compute},
+		{"empty", spirv(binary.LittleEndian, 1), ShaderReflection{}},
+	} {
+		got, err := reflectSPIRV(test.code)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestReflectSPIRVInvalid(t *testing.T) {
+	valid := computeSPIRV(binary.LittleEndian)
+	badMagic := append([]byte(nil), valid...)
+	badMagic[0] ^= 0xFF
+	zeroCount := spirv(binary.LittleEndian, 1, []uint32{17, 1})
+	binary.LittleEndian.PutUint32(zeroCount[20:], 17)
+	for _, test := range []struct {
+		name string
+		code []byte
+	}{
+		{"nil", nil},
+		{"header only", valid[:16]},
+		{"unaligned", valid[:len(valid)-1]},
+		{"bad magic", badMagic},
+		{"truncated instruction", valid[:len(valid)-4]},
+		{"zero word count", zeroCount},
+	} {
+		if _, err := reflectSPIRV(test.code); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
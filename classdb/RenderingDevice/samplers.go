@@ -0,0 +1,42 @@
+package RenderingDevice
+
+import (
+	"graphics.gd/classdb/RDSamplerState"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/Float"
+	"graphics.gd/variant/RID"
+)
+
+// SamplerForTexture creates a trilinear sampler (linear filtering between texels and
+// between mipmaps) with its level of detail range covering every mipmap of the texture.
+func SamplerForTexture(dev Instance, texture RID.Texture) RID.Sampler {
+	mipmaps := max(dev.TextureGetFormat(texture).Mipmaps(), 1)
+	state := RDSamplerState.New()
+	state.SetMagFilter(Rendering.SamplerFilterLinear)
+	state.SetMinFilter(Rendering.SamplerFilterLinear)
+	state.SetMipFilter(Rendering.SamplerFilterLinear)
+	state.SetMinLod(0)
+	state.SetMaxLod(Float.X(mipmaps - 1))
+	return dev.SamplerCreate(state)
+}
+
+// UnnormalizedSamplerState returns a sampler state that addresses textures with unnormalized
+// coordinates, measured in texels rather than in the range [0, 1]. Such samplers must use the same
+// filter for magnification and minification, clamp at the edges, sample the first mipmap only and
+// may not use anisotropy or comparison, so the state is configured accordingly.
+func UnnormalizedSamplerState(filter Rendering.SamplerFilter) RDSamplerState.Instance {
+	state := RDSamplerState.New()
+	state.SetUnnormalizedUvw(true)
+	state.SetMagFilter(filter)
+	state.SetMinFilter(filter)
+	state.SetMipFilter(Rendering.SamplerFilterNearest)
+	state.SetRepeatU(Rendering.SamplerRepeatModeClampToEdge)
+	state.SetRepeatV(Rendering.SamplerRepeatModeClampToEdge)
+	state.SetRepeatW(Rendering.SamplerRepeatModeClampToEdge)
+	state.SetMinLod(0)
+	state.SetMaxLod(0)
+	state.SetLodBias(0)
+	state.SetUseAnisotropy(false)
+	state.SetEnableCompare(false)
+	return state
+}
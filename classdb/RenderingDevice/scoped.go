@@ -0,0 +1,92 @@
+package RenderingDevice
+
+import "graphics.gd/variant/RID"
+
+// DrawList is a draw list that is being recorded by [Instance.DrawList].
+type DrawList struct {
+	dev Instance
+	ID  int
+}
+
+// DrawList begins a draw list for the framebuffer, calls fn to record it, and then ends it, even
+// if fn panics, so that the draw list is always balanced.
+func (self Instance) DrawList(framebuffer RID.Framebuffer, fn func(dl DrawList)) {
+	dl := DrawList{dev: self, ID: self.DrawListBegin(framebuffer)}
+	defer self.DrawListEnd()
+	fn(dl)
+}
+
+// BindRenderPipeline is like [Instance.DrawListBindRenderPipeline].
+func (dl DrawList) BindRenderPipeline(pipeline RID.RenderPipeline) {
+	dl.dev.DrawListBindRenderPipeline(dl.ID, pipeline)
+}
+
+// BindUniformSet is like [Instance.DrawListBindUniformSet].
+func (dl DrawList) BindUniformSet(set RID.UniformSet, index int) {
+	dl.dev.DrawListBindUniformSet(dl.ID, set, index)
+}
+
+// BindVertexArray is like [Instance.DrawListBindVertexArray].
+func (dl DrawList) BindVertexArray(array RID.VertexArray) {
+	dl.dev.DrawListBindVertexArray(dl.ID, array)
+}
+
+// BindIndexArray is like [Instance.DrawListBindIndexArray].
+func (dl DrawList) BindIndexArray(array RID.IndexArray) {
+	dl.dev.DrawListBindIndexArray(dl.ID, array)
+}
+
+// SetPushConstant is like [Instance.DrawListSetPushConstant], for the entire buffer.
+func (dl DrawList) SetPushConstant(buffer []byte) {
+	dl.dev.DrawListSetPushConstant(dl.ID, buffer, len(buffer))
+}
+
+// Draw is like [Instance.DrawListDraw].
+func (dl DrawList) Draw(use_indices bool, instances int) {
+	dl.dev.DrawListDraw(dl.ID, use_indices, instances)
+}
+
+// DrawProcedural is like [Expanded.DrawListDraw], drawing vertex_count vertices without a vertex
+// array, such as for a fullscreen triangle.
+func (dl DrawList) DrawProcedural(instances, vertex_count int) {
+	Expanded(dl.dev).DrawListDraw(dl.ID, false, instances, vertex_count)
+}
+
+// ComputeList is a compute list that is being recorded by [Instance.ComputeList].
+type ComputeList struct {
+	dev Instance
+	ID  int
+}
+
+// ComputeList begins a compute list, calls fn to record it, and then ends it, even if fn panics,
+// so that the compute list is always balanced.
+func (self Instance) ComputeList(fn func(cl ComputeList)) {
+	cl := ComputeList{dev: self, ID: self.ComputeListBegin()}
+	defer self.ComputeListEnd()
+	fn(cl)
+}
+
+// BindComputePipeline is like [Instance.ComputeListBindComputePipeline].
+func (cl ComputeList) BindComputePipeline(pipeline RID.ComputePipeline) {
+	cl.dev.ComputeListBindComputePipeline(cl.ID, pipeline)
+}
+
+// BindUniformSet is like [Instance.ComputeListBindUniformSet].
+func (cl ComputeList) BindUniformSet(set RID.UniformSet, index int) {
+	cl.dev.ComputeListBindUniformSet(cl.ID, set, index)
+}
+
+// SetPushConstant is like [Instance.ComputeListSetPushConstant], for the entire buffer.
+func (cl ComputeList) SetPushConstant(buffer []byte) {
+	cl.dev.ComputeListSetPushConstant(cl.ID, buffer, len(buffer))
+}
+
+// Dispatch is like [Instance.ComputeListDispatch].
+func (cl ComputeList) Dispatch(x_groups, y_groups, z_groups int) {
+	cl.dev.ComputeListDispatch(cl.ID, x_groups, y_groups, z_groups)
+}
+
+// AddBarrier is like [Instance.ComputeListAddBarrier].
+func (cl ComputeList) AddBarrier() {
+	cl.dev.ComputeListAddBarrier(cl.ID)
+}
@@ -0,0 +1,145 @@
+package RenderingDevice
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"graphics.gd/classdb/Rendering"
+
+	gd "graphics.gd/internal"
+	"graphics.gd/internal/callframe"
+	"graphics.gd/internal/gdclass"
+	"graphics.gd/internal/pointers"
+	"graphics.gd/variant/Callable"
+	"graphics.gd/variant/RID"
+)
+
+// The RenderingServer package imports this one, so the few RenderingServer methods needed here
+// are called directly.
+var server struct {
+	once sync.Once
+	self [1]gdclass.RenderingServer
+}
+
+func renderingServer() [1]gdclass.RenderingServer {
+	server.once.Do(func() {
+		obj := gd.Global.Object.GetSingleton(gd.Global.Singletons.RenderingServer)
+		server.self = *(*[1]gdclass.RenderingServer)(unsafe.Pointer(&obj))
+	})
+	return server.self
+}
+
+// mainDevice returns the global rendering device, if there is one (there isn't when using the
+// OpenGL rendering driver or when running in headless mode).
+func mainDevice() (Instance, bool) { //gd:RenderingServer.get_rendering_device
+	var frame = callframe.New()
+	var r_ret = callframe.Ret[gd.EnginePointer](frame)
+	gd.Global.Object.MethodBindPointerCall(gd.Global.Methods.RenderingServer.Bind_get_rendering_device, renderingServer()[0].AsObject(), frame.Array(0), r_ret.Addr())
+	var ptr = r_ret.Get()
+	frame.Free()
+	if ptr == 0 {
+		return Instance{}, false
+	}
+	return Instance{gd.PointerBorrowedTemporarily[gdclass.RenderingDevice](ptr)}, true
+}
+
+// isMainDevice reports whether the device is the global rendering device, as opposed to a
+// local rendering device.
+func isMainDevice(dev Instance) bool {
+	main, ok := mainDevice()
+	return ok && main.ID() == dev.ID()
+}
+
+// callOnRenderThread schedules f to be called on the render thread, where the main rendering
+// device may be used. When the rendering server runs on the calling thread, f is called before
+// returning.
+func callOnRenderThread(f func()) { //gd:RenderingServer.call_on_render_thread
+	var frame = callframe.New()
+	callframe.Arg(frame, pointers.Get(gd.InternalCallable(Callable.New(f))))
+	var r_ret = callframe.Nil
+	gd.Global.Object.MethodBindPointerCall(gd.Global.Methods.RenderingServer.Bind_call_on_render_thread, renderingServer()[0].AsObject(), frame.Array(0), r_ret.Addr())
+	frame.Free()
+}
+
+// videoAdapterType returns the type of the video adapter used by the rendering server.
+func videoAdapterType() Rendering.DeviceType { //gd:RenderingServer.get_video_adapter_type
+	var frame = callframe.New()
+	var r_ret = callframe.Ret[Rendering.DeviceType](frame)
+	gd.Global.Object.MethodBindPointerCall(gd.Global.Methods.RenderingServer.Bind_get_video_adapter_type, renderingServer()[0].AsObject(), frame.Array(0), r_ret.Addr())
+	var ret = r_ret.Get()
+	frame.Free()
+	return ret
+}
+
+// NewLocalDevice creates a new local rendering device, for running compute (or offscreen rendering)
+// independently of the main rendering device, such as in standalone GPGPU tools. Local devices
+// cannot draw to the screen nor share resources with the main rendering device, and must be
+// submitted and synced explicitly. An error is returned when local devices are unavailable, which
+// is the case with the Compatibility (OpenGL) renderer and in headless mode.
+func NewLocalDevice() (Instance, error) { //gd:RenderingServer.create_local_rendering_device
+	var frame = callframe.New()
+	var r_ret = callframe.Ret[gd.EnginePointer](frame)
+	gd.Global.Object.MethodBindPointerCall(gd.Global.Methods.RenderingServer.Bind_create_local_rendering_device, renderingServer()[0].AsObject(), frame.Array(0), r_ret.Addr())
+	var ptr = r_ret.Get()
+	frame.Free()
+	if ptr == 0 {
+		return Instance{}, errors.New("local rendering devices are not available with the current renderer")
+	}
+	return Instance{gd.PointerWithOwnershipTransferredToGo[gdclass.RenderingDevice](ptr)}, nil
+}
+
+// FreeLocalDevice frees a local rendering device created with [NewLocalDevice], along with the
+// Go-side state kept for it by the helpers in this package (such as compiled kernels, shader
+// reflections and tracked resources). Freeing a local device by other means leaves that state
+// behind. The main rendering device is never freed.
+func FreeLocalDevice(dev Instance) {
+	if isMainDevice(dev) {
+		return
+	}
+	states.Delete(dev.ID())
+	dev.AsObject()[0].Free()
+}
+
+// TextureFromRenderingServer returns the texture of the main rendering device that backs the given
+// RenderingServer texture (such as a viewport texture or the texture of a Texture2D resource), so
+// that it can be processed by compute shaders. The texture is owned by the RenderingServer and
+// must not be freed. An error is returned if dev is not the main rendering device, as local
+// devices cannot access the RenderingServer's textures.
+func TextureFromRenderingServer(dev Instance, texture RID.Any) (RID.Texture, error) { //gd:RenderingServer.texture_get_rd_texture
+	if !isMainDevice(dev) {
+		return RID.Texture(0), errors.New("RenderingServer textures can only be used on the main rendering device")
+	}
+	var frame = callframe.New()
+	callframe.Arg(frame, texture)
+	callframe.Arg(frame, false)
+	var r_ret = callframe.Ret[RID.Any](frame)
+	gd.Global.Object.MethodBindPointerCall(gd.Global.Methods.RenderingServer.Bind_texture_get_rd_texture, renderingServer()[0].AsObject(), frame.Array(0), r_ret.Addr())
+	var ret = r_ret.Get()
+	frame.Free()
+	if ret == 0 {
+		return RID.Texture(0), fmt.Errorf("RenderingServer texture %v has no RenderingDevice texture", texture)
+	}
+	return RID.Texture(ret), nil
+}
+
+// TextureToRenderingServer creates a RenderingServer texture that uses the 2D texture of the main
+// rendering device, so that the results of compute shaders can be displayed by nodes (for example,
+// by assigning it to a Texture2DRD). Free the returned texture with the RenderingServer.
+func TextureToRenderingServer(dev Instance, texture RID.Texture) (RID.Any, error) { //gd:RenderingServer.texture_rd_create
+	if !isMainDevice(dev) {
+		return 0, errors.New("only textures of the main rendering device can be used by the RenderingServer")
+	}
+	var frame = callframe.New()
+	callframe.Arg(frame, RID.Any(texture))
+	callframe.Arg(frame, int64(0)) // RenderingServer.TextureLayered2dArray, ignored for 2D textures.
+	var r_ret = callframe.Ret[RID.Any](frame)
+	gd.Global.Object.MethodBindPointerCall(gd.Global.Methods.RenderingServer.Bind_texture_rd_create, renderingServer()[0].AsObject(), frame.Array(0), r_ret.Addr())
+	var ret = r_ret.Get()
+	frame.Free()
+	if ret == 0 {
+		return 0, fmt.Errorf("failed to create RenderingServer texture for %v", texture)
+	}
+	return ret, nil
+}
@@ -0,0 +1,95 @@
+package RenderingDevice
+
+import (
+	"sync"
+
+	"graphics.gd/variant/RID"
+)
+
+// state is the Go-side bookkeeping kept for each device used with the helpers in
+// this package.
+type state struct {
+	mutex   sync.Mutex
+	kernels map[string]kernel
+	shaders map[RID.Shader]ShaderReflection
+	tracked map[RID.Any]struct{}
+	sets    map[RID.UniformSet]UniformSetInfo
+	formats map[attachmentLayout]int
+	pending int           // outstanding async requests.
+	drained chan struct{} // closed once pending drops back to zero.
+	frames  int64         // submitted with SubmitTracked.
+	vertex  map[int][]vertexAttribute
+	layouts map[string]int  // vertex formats by layout.
+	sizes   map[RID.Any]int // buffer sizes in bytes.
+	targets map[RID.Framebuffer][]RID.Texture
+	garbage []RID.Any            // collected OwnedRIDs to free.
+	freed   map[RID.Any]struct{} // with EnableFreeTracking.
+	panics  bool                 // on double frees, instead of warning.
+	history []RID.Any            // freed, in order, at most freedLimit.
+	oldest  int                  // index in history, once full.
+	caps    *Capabilities
+	queued  []func() // to call from SubmitTracked.
+}
+
+var states sync.Map // map[ID]*state
+
+// stateOf returns the Go-side state for the given device, which remains until the device is
+// freed with [FreeLocalDevice].
+func stateOf(dev Instance) *state {
+	id := dev.ID()
+	if s, ok := states.Load(id); ok {
+		return s.(*state)
+	}
+	s, _ := states.LoadOrStore(id, new(state))
+	return s.(*state)
+}
+
+// track records that the resources were created on the device by this package.
+func (s *state) track(rids ...RID.Any) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.tracked == nil {
+		s.tracked = make(map[RID.Any]struct{})
+	}
+	for _, rid := range rids {
+		s.tracked[rid] = struct{}{}
+	}
+}
+
+// sized records the size in bytes of a buffer created on the device, so that reads and writes
+// through [Instance.BufferWriterAt] and [Instance.BufferReaderAt] can be bounds checked.
+func (s *state) sized(rid RID.Any, size int) {
+	if rid == 0 {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.sizes == nil {
+		s.sizes = make(map[RID.Any]int)
+	}
+	s.sizes[rid] = size
+}
+
+// owns reports whether the resource was created on the device by this package.
+func (s *state) owns(rid RID.Any) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.tracked[rid]
+	return ok
+}
+
+// Owns reports whether the resource belongs to this device, either because it was created by one
+// of the helpers in this package, or because the device recognises it as one of its textures,
+// framebuffers, uniform sets or pipelines. Resources from one device must never be used on
+// another, so functions accepting both a device and resources can use this to validate them.
+func (self Instance) Owns(rid RID.Any) bool {
+	if rid == 0 {
+		return false
+	}
+	return stateOf(self).owns(rid) ||
+		self.TextureIsValid(RID.Texture(rid)) ||
+		self.FramebufferIsValid(RID.Framebuffer(rid)) ||
+		self.UniformSetIsValid(RID.UniformSet(rid)) ||
+		self.RenderPipelineIsValid(RID.RenderPipeline(rid)) ||
+		self.ComputePipelineIsValid(RID.ComputePipeline(rid))
+}
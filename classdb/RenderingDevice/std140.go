@@ -0,0 +1,97 @@
+package RenderingDevice
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// std140 returns the size and base alignment of the Go type when laid out as per the std140
+// rules of GLSL uniform blocks. bool, int32, uint32 and float32 are scalars, float64 is a double,
+// arrays of two to four 32-bit scalars are vectors, structs are structs and any other arrays are
+// arrays, with their stride rounded up to 16 bytes.
+func std140(t reflect.Type) (size, align int, err error) {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int32, reflect.Uint32, reflect.Float32:
+		return 4, 4, nil
+	case reflect.Float64:
+		return 8, 8, nil
+	case reflect.Array:
+		if isStd140Vector(t) {
+			if t.Len() == 2 {
+				return 8, 8, nil
+			}
+			return t.Len() * 4, 16, nil
+		}
+		elem, _, err := std140(t.Elem())
+		if err != nil {
+			return 0, 0, err
+		}
+		stride := (elem + 15) &^ 15
+		return stride * t.Len(), 16, nil
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field, falign, err := std140(t.Field(i).Type)
+			if err != nil {
+				return 0, 0, err
+			}
+			size = (size+falign-1)&^(falign-1) + field
+			align = max(align, falign)
+		}
+		align = (max(align, 1) + 15) &^ 15
+		return (size + align - 1) &^ (align - 1), align, nil
+	default:
+		return 0, 0, fmt.Errorf("%v has no std140 layout", t)
+	}
+}
+
+// isStd140Vector reports whether the array type is laid out as a GLSL vector.
+func isStd140Vector(t reflect.Type) bool {
+	if t.Len() < 2 || t.Len() > 4 {
+		return false
+	}
+	switch t.Elem().Kind() {
+	case reflect.Bool, reflect.Int32, reflect.Uint32, reflect.Float32:
+		return true
+	default:
+		return false
+	}
+}
+
+// packStd140 writes the value into dst, which must be large enough to hold its std140 layout.
+func packStd140(dst []byte, value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Bool:
+		if value.Bool() {
+			binary.LittleEndian.PutUint32(dst, 1)
+		} else {
+			binary.LittleEndian.PutUint32(dst, 0)
+		}
+	case reflect.Int32:
+		binary.LittleEndian.PutUint32(dst, uint32(value.Int()))
+	case reflect.Uint32:
+		binary.LittleEndian.PutUint32(dst, uint32(value.Uint()))
+	case reflect.Float32:
+		binary.LittleEndian.PutUint32(dst, math.Float32bits(float32(value.Float())))
+	case reflect.Float64:
+		binary.LittleEndian.PutUint64(dst, math.Float64bits(value.Float()))
+	case reflect.Array:
+		stride := 4
+		if !isStd140Vector(value.Type()) {
+			elem, _, _ := std140(value.Type().Elem())
+			stride = (elem + 15) &^ 15
+		}
+		for i := range value.Len() {
+			packStd140(dst[i*stride:], value.Index(i))
+		}
+	case reflect.Struct:
+		offset := 0
+		for i := range value.NumField() {
+			size, align, _ := std140(value.Field(i).Type())
+			offset = (offset + align - 1) &^ (align - 1)
+			packStd140(dst[offset:], value.Field(i))
+			offset += size
+		}
+	}
+}
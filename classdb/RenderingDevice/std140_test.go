@@ -0,0 +1,128 @@
+package RenderingDevice
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// le encodes each of the values as a little-endian 32-bit word, so that expected layouts can be
+// spelled out word by word (floats are given as float32, everything else as uint32).
+func le(words ...any) []byte {
+	var buf []byte
+	for _, word := range words {
+		switch word := word.(type) {
+		case float32:
+			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(word))
+		case int:
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(word))
+		}
+	}
+	return buf
+}
+
+func TestStd140(t *testing.T) {
+	type Light struct {
+		Position  [3]float32 // vec3, offset 0
+		Intensity float32    // float, packed after the vec3 at offset 12
+	}
+	var identity [4][4]float32
+	for i := range identity {
+		identity[i][i] = 1
+	}
+	for _, test := range []struct {
+		name  string
+		value any
+		size  int
+		align int
+		want  []byte
+	}{
+		{
+			name:  "vec3 followed by float",
+			value: Light{Position: [3]float32{1, 2, 3}, Intensity: 4},
+			size:  16, align: 16,
+			want: le(float32(1), float32(2), float32(3), float32(4)),
+		},
+		{
+			name: "float followed by vec2",
+			value: struct {
+				A float32
+				B [2]float32 // vec2, aligned to 8
+			}{1, [2]float32{2, 3}},
+			size: 16, align: 16,
+			want: le(float32(1), 0, float32(2), float32(3)),
+		},
+		{
+			name:  "array of scalars",
+			value: [5]uint32{1, 2, 3, 4, 5}, // uint[5], each element padded to 16 bytes
+			size:  80, align: 16,
+			want: le(1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0, 4, 0, 0, 0, 5, 0, 0, 0),
+		},
+		{
+			name:  "mat4",
+			value: identity, // four vec4 columns
+			size:  64, align: 16,
+			want: le(
+				float32(1), 0, 0, 0,
+				0, float32(1), 0, 0,
+				0, 0, float32(1), 0,
+				0, 0, 0, float32(1),
+			),
+		},
+		{
+			name: "nested struct",
+			value: struct {
+				Count uint32 // offset 0
+				Light Light  // struct, aligned to 16
+				Scale float32
+			}{7, Light{[3]float32{1, 2, 3}, 4}, 5},
+			size: 48, align: 16,
+			want: le(
+				7, 0, 0, 0,
+				float32(1), float32(2), float32(3), float32(4),
+				float32(5), 0, 0, 0,
+			),
+		},
+		{
+			name: "mat4 and array after scalar",
+			value: struct {
+				Flag      bool          // offset 0
+				Transform [4][4]float32 // offset 16
+				Weights   [2]int32      // ivec2, offset 80
+			}{true, identity, [2]int32{-1, 2}},
+			size: 96, align: 16,
+			want: le(
+				1, 0, 0, 0,
+				float32(1), 0, 0, 0,
+				0, float32(1), 0, 0,
+				0, 0, float32(1), 0,
+				0, 0, 0, float32(1),
+				0xFFFFFFFF, 2, 0, 0,
+			),
+		},
+	} {
+		size, align, err := std140(reflect.TypeOf(test.value))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if size != test.size || align != test.align {
+			t.Errorf("%s: size %d, align %d, want size %d, align %d", test.name, size, align, test.size, test.align)
+		}
+		got := make([]byte, size)
+		packStd140(got, reflect.ValueOf(test.value))
+		if !bytes.Equal(got, test.want) {
+			t.Errorf("%s: packed\n% x\nwant\n% x", test.name, got, test.want)
+		}
+	}
+}
+
+func TestStd140Unsupported(t *testing.T) {
+	for _, value := range []any{int64(0), "", []float32{}, struct{ P *int }{}} {
+		if _, _, err := std140(reflect.TypeOf(value)); err == nil {
+			t.Errorf("%T: expected an error", value)
+		}
+	}
+}
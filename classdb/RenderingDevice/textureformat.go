@@ -0,0 +1,112 @@
+package RenderingDevice
+
+import (
+	"fmt"
+
+	"graphics.gd/classdb/RDTextureFormat"
+	"graphics.gd/classdb/Rendering"
+)
+
+// TextureFormatBuilder builds an [RDTextureFormat.Instance] for [Instance.TextureCreate], see
+// [NewTextureFormat].
+type TextureFormatBuilder struct {
+	kind    Rendering.TextureType
+	format  Rendering.DataFormat
+	width   int
+	height  int
+	depth   int
+	layers  int
+	mipmaps int
+	samples Rendering.TextureSamples
+	usage   Rendering.TextureUsageBits
+}
+
+// NewTextureFormat returns a builder for a single sampled 2D texture, with one mipmap, one layer
+// and a single sample per pixel, in [Rendering.DataFormatR8g8b8a8Unorm] format. The size must be
+// set before calling [TextureFormatBuilder.Build], such as:
+//
+//	format := RenderingDevice.NewTextureFormat().Size2D(256, 256).Usage(Rendering.TextureUsageColorAttachmentBit).Build()
+func NewTextureFormat() TextureFormatBuilder {
+	return TextureFormatBuilder{
+		kind:    Rendering.TextureType2d,
+		format:  Rendering.DataFormatR8g8b8a8Unorm,
+		depth:   1,
+		layers:  1,
+		mipmaps: 1,
+		samples: Rendering.TextureSamples1,
+		usage:   Rendering.TextureUsageSamplingBit,
+	}
+}
+
+// Size2D sets the size of a 2D texture.
+func (b TextureFormatBuilder) Size2D(width, height int) TextureFormatBuilder {
+	b.kind, b.width, b.height, b.depth = Rendering.TextureType2d, width, height, 1
+	return b
+}
+
+// Size3D sets the size of a 3D texture.
+func (b TextureFormatBuilder) Size3D(width, height, depth int) TextureFormatBuilder {
+	b.kind, b.width, b.height, b.depth = Rendering.TextureType3d, width, height, depth
+	return b
+}
+
+// Type overrides the texture type, such as for cube maps or texture arrays.
+func (b TextureFormatBuilder) Type(kind Rendering.TextureType) TextureFormatBuilder {
+	b.kind = kind
+	return b
+}
+
+// Format sets the data format of the texels.
+func (b TextureFormatBuilder) Format(format Rendering.DataFormat) TextureFormatBuilder {
+	b.format = format
+	return b
+}
+
+// Usage adds to the usage bits of the texture, which include [Rendering.TextureUsageSamplingBit]
+// unless [TextureFormatBuilder.UsageOnly] is used instead.
+func (b TextureFormatBuilder) Usage(usage Rendering.TextureUsageBits) TextureFormatBuilder {
+	b.usage |= usage
+	return b
+}
+
+// UsageOnly replaces the usage bits of the texture.
+func (b TextureFormatBuilder) UsageOnly(usage Rendering.TextureUsageBits) TextureFormatBuilder {
+	b.usage = usage
+	return b
+}
+
+// Mipmaps sets the number of mipmaps, see [MipLevelCount] for a full mipmap chain.
+func (b TextureFormatBuilder) Mipmaps(mipmaps int) TextureFormatBuilder {
+	b.mipmaps = mipmaps
+	return b
+}
+
+// ArrayLayers sets the number of layers of a texture array or cube map.
+func (b TextureFormatBuilder) ArrayLayers(layers int) TextureFormatBuilder {
+	b.layers = layers
+	return b
+}
+
+// Samples sets the number of samples per pixel of a multisampled texture.
+func (b TextureFormatBuilder) Samples(samples Rendering.TextureSamples) TextureFormatBuilder {
+	b.samples = samples
+	return b
+}
+
+// Build returns the texture format. Panics if the width or height has not been set.
+func (b TextureFormatBuilder) Build() RDTextureFormat.Instance {
+	if b.width <= 0 || b.height <= 0 {
+		panic(fmt.Sprintf("RenderingDevice.NewTextureFormat: invalid texture size %vx%v", b.width, b.height))
+	}
+	tf := RDTextureFormat.New()
+	tf.SetTextureType(b.kind)
+	tf.SetFormat(b.format)
+	tf.SetWidth(b.width)
+	tf.SetHeight(b.height)
+	tf.SetDepth(b.depth)
+	tf.SetArrayLayers(b.layers)
+	tf.SetMipmaps(b.mipmaps)
+	tf.SetSamples(b.samples)
+	tf.SetUsageBits(b.usage)
+	return tf
+}
@@ -0,0 +1,156 @@
+package RenderingDevice
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"graphics.gd/classdb/RDTextureFormat"
+	"graphics.gd/classdb/RDTextureView"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/Vector2i"
+)
+
+// texturePoolKey identifies interchangeable textures in a [TexturePool].
+type texturePoolKey struct {
+	size   Vector2i.XY
+	format Rendering.DataFormat
+	usage  Rendering.TextureUsageBits
+}
+
+// TexturePool hands out transient 2D textures, such as the scratch targets of a post-processing
+// chain, reusing released textures of the same size, format and usage rather than creating and
+// freeing them every frame. A TexturePool is safe for concurrent use.
+type TexturePool struct {
+	dev   Instance
+	mutex sync.Mutex
+	free  map[texturePoolKey][]RID.Texture
+	used  map[RID.Texture]texturePoolKey
+
+	budget int // maximum bytes, or zero for no limit.
+	bytes  int // bytes of the textures held by the pool.
+}
+
+// ErrBudgetExceeded is returned when creating a resource would exceed the memory budget.
+var ErrBudgetExceeded = errors.New("memory budget exceeded")
+
+// NewTexturePool returns an empty [TexturePool] for the device.
+func NewTexturePool(dev Instance) *TexturePool {
+	return &TexturePool{
+		dev:  dev,
+		free: make(map[texturePoolKey][]RID.Texture),
+		used: make(map[RID.Texture]texturePoolKey),
+	}
+}
+
+// NewTexturePoolWithBudget returns an empty [TexturePool] for the device, that fails to create
+// textures with [ErrBudgetExceeded] when the textures it holds (both acquired and released) would
+// take up more than max_bytes of video memory. Released textures are freed to make room before
+// failing.
+func NewTexturePoolWithBudget(dev Instance, max_bytes int) *TexturePool {
+	pool := NewTexturePool(dev)
+	pool.budget = max_bytes
+	return pool
+}
+
+// Bytes returns the video memory, in bytes, taken up by the textures held by the pool.
+func (p *TexturePool) Bytes() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.bytes
+}
+
+// Acquire returns a texture with the given size, format and usage, reusing a released one if
+// available. The texture's contents are undefined. Pools with a budget cannot create textures of
+// subsampled or multi-planar formats, as their size is unknown.
+func (p *TexturePool) Acquire(size Vector2i.XY, format Rendering.DataFormat, usage Rendering.TextureUsageBits) (RID.Texture, error) {
+	key := texturePoolKey{size: size, format: format, usage: usage}
+	p.mutex.Lock()
+	if free := p.free[key]; len(free) > 0 {
+		defer p.mutex.Unlock()
+		texture := free[len(free)-1]
+		p.free[key] = free[:len(free)-1]
+		p.used[texture] = key
+		return texture, nil
+	}
+	if size.X <= 0 || size.Y <= 0 {
+		p.mutex.Unlock()
+		return RID.Texture(0), fmt.Errorf("invalid texture size %v", size)
+	}
+	bytes := formatImageSize(format, int(size.X), int(size.Y))
+	var trimmed []RID.Texture
+	if p.budget > 0 {
+		if bytes == 0 {
+			p.mutex.Unlock()
+			return RID.Texture(0), fmt.Errorf("size of format %v is unknown, so it cannot be budgeted", format)
+		}
+		if p.bytes+bytes > p.budget {
+			trimmed = p.trim()
+			if p.bytes+bytes > p.budget {
+				err := fmt.Errorf("%vx%v texture needs %d bytes, %d of %d in use: %w", size.X, size.Y, bytes, p.bytes, p.budget, ErrBudgetExceeded)
+				p.mutex.Unlock()
+				p.freeTextures(trimmed)
+				return RID.Texture(0), err
+			}
+		}
+	}
+	p.bytes += bytes // reserved while the texture is created.
+	p.mutex.Unlock()
+	p.freeTextures(trimmed)
+	tf := RDTextureFormat.New()
+	tf.SetTextureType(Rendering.TextureType2d)
+	tf.SetFormat(format)
+	tf.SetWidth(int(size.X))
+	tf.SetHeight(int(size.Y))
+	tf.SetUsageBits(usage)
+	texture := p.dev.TextureCreate(tf, RDTextureView.New())
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if texture == RID.Texture(0) {
+		p.bytes -= bytes
+		return texture, fmt.Errorf("failed to create %vx%v texture", size.X, size.Y)
+	}
+	p.used[texture] = key
+	return texture, nil
+}
+
+// Release returns a texture acquired from the pool, so that it can be reused by a later call to
+// [TexturePool.Acquire]. The texture must no longer be used by the caller. Textures that were not
+// acquired from the pool are ignored.
+func (p *TexturePool) Release(texture RID.Texture) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	key, ok := p.used[texture]
+	if !ok {
+		return
+	}
+	delete(p.used, texture)
+	p.free[key] = append(p.free[key], texture)
+}
+
+// Trim frees each released texture that has not been reacquired.
+func (p *TexturePool) Trim() {
+	p.mutex.Lock()
+	trimmed := p.trim()
+	p.mutex.Unlock()
+	p.freeTextures(trimmed)
+}
+
+// trim removes each released texture from the pool, returning them to be freed once the pool is
+// unlocked.
+func (p *TexturePool) trim() []RID.Texture {
+	var trimmed []RID.Texture
+	for key, free := range p.free {
+		trimmed = append(trimmed, free...)
+		p.bytes -= len(free) * formatImageSize(key.format, int(key.size.X), int(key.size.Y))
+		delete(p.free, key)
+	}
+	return trimmed
+}
+
+func (p *TexturePool) freeTextures(textures []RID.Texture) {
+	for _, texture := range textures {
+		p.dev.FreeRidTracked(RID.Any(texture))
+	}
+}
@@ -0,0 +1,316 @@
+package RenderingDevice
+
+import (
+	"fmt"
+	"math/bits"
+	"unsafe"
+
+	"graphics.gd/classdb/RDTextureFormat"
+	"graphics.gd/classdb/RDTextureView"
+	"graphics.gd/classdb/Rendering"
+	gd "graphics.gd/internal"
+	"graphics.gd/internal/callframe"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+)
+
+// TexturesSetDiscardable updates the discardable property of each of the given textures,
+// see [Instance.TextureSetDiscardable]. Useful for marking every target of a G-buffer at once.
+func (self Instance) TexturesSetDiscardable(discardable bool, textures ...RID.Texture) {
+	if len(textures) == 0 {
+		return
+	}
+	var frame = callframe.New()
+	var texture = callframe.Arg(frame, RID.Any(0))
+	callframe.Arg(frame, discardable)
+	var r_ret = callframe.Nil
+	for _, rid := range textures {
+		*(*RID.Any)(texture.UnsafePointer()) = RID.Any(rid)
+		gd.Global.Object.MethodBindPointerCall(gd.Global.Methods.RenderingDevice.Bind_texture_set_discardable, self.AsObject(), frame.Array(0), r_ret.Addr())
+	}
+	frame.Free()
+}
+
+// TextureSize returns the width and height of the texture, as per its format.
+func TextureSize(dev Instance, texture RID.Texture) Vector2i.XY {
+	format := dev.TextureGetFormat(texture)
+	return Vector2i.XY{int32(format.Width()), int32(format.Height())}
+}
+
+// TextureRect returns a rectangle at the origin that covers the entire texture, suitable
+// for use as a viewport or scissor region when rendering to the texture.
+func TextureRect(dev Instance, texture RID.Texture) Rect2.PositionSize {
+	size := TextureSize(dev, texture)
+	return Rect2.PositionSize{Size: Vector2.New(size.X, size.Y)}
+}
+
+// TextureCreateComputeIO creates a 2D texture that compute shaders can both sample and write
+// to as a storage image, and that can be copied to and from, such as the ping-pong targets of
+// an iterative image filter. An error is returned if the device does not support the format for
+// all of these usages.
+func TextureCreateComputeIO(dev Instance, size Vector2i.XY, format Rendering.DataFormat) (RID.Texture, error) {
+	const usage = Rendering.TextureUsageSamplingBit | Rendering.TextureUsageStorageBit |
+		Rendering.TextureUsageCanCopyFromBit | Rendering.TextureUsageCanCopyToBit
+	if size.X <= 0 || size.Y <= 0 {
+		return RID.Texture(0), fmt.Errorf("invalid texture size %v", size)
+	}
+	if !dev.TextureIsFormatSupportedForUsage(format, usage) {
+		return RID.Texture(0), fmt.Errorf("format %v cannot be used for both sampling and storage", format)
+	}
+	tf := RDTextureFormat.New()
+	tf.SetTextureType(Rendering.TextureType2d)
+	tf.SetFormat(format)
+	tf.SetWidth(int(size.X))
+	tf.SetHeight(int(size.Y))
+	tf.SetUsageBits(usage)
+	texture := dev.TextureCreate(tf, RDTextureView.New())
+	if texture == RID.Texture(0) {
+		return texture, fmt.Errorf("failed to create %vx%v texture", size.X, size.Y)
+	}
+	return texture, nil
+}
+
+// TextureGetTexel returns the raw bytes of a single texel of the texture, in the texture's data
+// format, by copying it into a 1x1 staging texture and reading that back. This avoids retrieving
+// the entire texture when only one value is needed, for example when picking the object under the
+// mouse cursor. The texture must have been created with [Rendering.TextureUsageCanCopyFromBit].
+//
+// Note: This function blocks the GPU until the data is retrieved.
+func TextureGetTexel(dev Instance, texture RID.Texture, x, y, layer int) ([]byte, error) {
+	format := dev.TextureGetFormat(texture)
+	if x < 0 || y < 0 || x >= format.Width() || y >= format.Height() {
+		return nil, fmt.Errorf("texel (%v, %v) is outside of the %vx%v texture", x, y, format.Width(), format.Height())
+	}
+	if layer < 0 || layer >= max(format.ArrayLayers(), 1) {
+		return nil, fmt.Errorf("layer %v is outside of the texture", layer)
+	}
+	if format.UsageBits()&Rendering.TextureUsageCanCopyFromBit == 0 {
+		return nil, fmt.Errorf("texture %v cannot be copied from", texture)
+	}
+	tf := RDTextureFormat.New()
+	tf.SetTextureType(Rendering.TextureType2d)
+	tf.SetFormat(format.Format())
+	tf.SetWidth(1)
+	tf.SetHeight(1)
+	tf.SetUsageBits(Rendering.TextureUsageCanCopyToBit | Rendering.TextureUsageCanCopyFromBit)
+	staging := dev.TextureCreate(tf, RDTextureView.New())
+	if staging == RID.Texture(0) {
+		return nil, fmt.Errorf("failed to create staging texture for format %v", format.Format())
+	}
+	defer dev.FreeRidTracked(RID.Any(staging))
+	if err := dev.TextureCopy(texture, staging, Vector3.New(x, y, 0), Vector3.XYZ{}, Vector3.New(1, 1, 1), 0, 0, layer, 0); err != nil {
+		return nil, err
+	}
+	return dev.TextureGetData(staging, 0), nil
+}
+
+// TextureCreate3D creates a 3D (volume) texture of the given size, with data containing each of
+// the depth slices one after the other, each slice being rows of width texels from top to bottom.
+// The length of data must match the size of the volume exactly, and the format must have a fixed
+// texel size (block compressed formats are not supported).
+func TextureCreate3D(dev Instance, width, height, depth int, format Rendering.DataFormat, data []byte) (RID.Texture, error) {
+	if width <= 0 || height <= 0 || depth <= 0 {
+		return RID.Texture(0), fmt.Errorf("invalid volume size %vx%vx%v", width, height, depth)
+	}
+	texel := formatPixelSize(format)
+	if texel == 0 {
+		return RID.Texture(0), fmt.Errorf("format %v does not have a fixed texel size", format)
+	}
+	if expected := width * height * depth * texel; len(data) != expected {
+		return RID.Texture(0), fmt.Errorf("volume data is %v bytes, expected %v bytes", len(data), expected)
+	}
+	tf := RDTextureFormat.New()
+	tf.SetTextureType(Rendering.TextureType3d)
+	tf.SetFormat(format)
+	tf.SetWidth(width)
+	tf.SetHeight(height)
+	tf.SetDepth(depth)
+	tf.SetUsageBits(Rendering.TextureUsageSamplingBit | Rendering.TextureUsageCanUpdateBit | Rendering.TextureUsageCanCopyFromBit)
+	texture := Expanded(dev).TextureCreate(tf, RDTextureView.New(), [][]byte{data})
+	if texture == RID.Texture(0) {
+		return texture, fmt.Errorf("failed to create %vx%vx%v texture", width, height, depth)
+	}
+	return texture, nil
+}
+
+// TextureClone creates a new texture with the same format as the source texture, and copies every
+// mipmap of every layer of the source into it, such as when keeping the previous frame's render
+// target as a history buffer for temporal effects. The source texture must have been created with
+// [Rendering.TextureUsageCanCopyFromBit], the clone has the same usage bits in addition to
+// [Rendering.TextureUsageCanCopyToBit].
+func TextureClone(dev Instance, src RID.Texture) (RID.Texture, error) {
+	format := dev.TextureGetFormat(src)
+	if format.UsageBits()&Rendering.TextureUsageCanCopyFromBit == 0 {
+		return RID.Texture(0), fmt.Errorf("texture %v cannot be copied from", src)
+	}
+	format.SetUsageBits(format.UsageBits() | Rendering.TextureUsageCanCopyToBit)
+	clone := dev.TextureCreate(format, RDTextureView.New())
+	if clone == RID.Texture(0) {
+		return clone, fmt.Errorf("failed to create clone of texture %v", src)
+	}
+	width, height, depth := format.Width(), format.Height(), max(format.Depth(), 1)
+	for mipmap := range max(format.Mipmaps(), 1) {
+		size := Vector3.New(max(width>>mipmap, 1), max(height>>mipmap, 1), max(depth>>mipmap, 1))
+		for layer := range max(format.ArrayLayers(), 1) {
+			if err := dev.TextureCopy(src, clone, Vector3.XYZ{}, Vector3.XYZ{}, size, mipmap, mipmap, layer, layer); err != nil {
+				dev.FreeRidTracked(RID.Any(clone))
+				return RID.Texture(0), err
+			}
+		}
+	}
+	return clone, nil
+}
+
+// MipLevelCount returns the number of mipmaps in a full mipmap chain for a texture of the given
+// size, from the full size texture down to 1x1, that is floor(log2(max(width, height))) + 1.
+func MipLevelCount(size Vector2i.XY) int {
+	return bits.Len32(uint32(max(size.X, size.Y, 1)))
+}
+
+// MipLevelCount3D returns the number of mipmaps in a full mipmap chain for a 3D texture of the
+// given size, see [MipLevelCount].
+func MipLevelCount3D(width, height, depth int) int {
+	return bits.Len(uint(max(width, height, depth, 1)))
+}
+
+// TextureUsage returns the usage bits that the texture was created with.
+func TextureUsage(dev Instance, texture RID.Texture) Rendering.TextureUsageBits {
+	return dev.TextureGetFormat(texture).UsageBits()
+}
+
+var textureUsageNames = []struct {
+	bit  Rendering.TextureUsageBits
+	name string
+}{
+	{Rendering.TextureUsageSamplingBit, "Sampling"},
+	{Rendering.TextureUsageColorAttachmentBit, "ColorAttachment"},
+	{Rendering.TextureUsageDepthStencilAttachmentBit, "DepthStencilAttachment"},
+	{Rendering.TextureUsageStorageBit, "Storage"},
+	{Rendering.TextureUsageStorageAtomicBit, "StorageAtomic"},
+	{Rendering.TextureUsageCpuReadBit, "CpuRead"},
+	{Rendering.TextureUsageCanUpdateBit, "CanUpdate"},
+	{Rendering.TextureUsageCanCopyFromBit, "CanCopyFrom"},
+	{Rendering.TextureUsageCanCopyToBit, "CanCopyTo"},
+	{Rendering.TextureUsageInputAttachmentBit, "InputAttachment"},
+}
+
+// TextureUsageNames returns the names of each of the usage bits that are set, such as
+// "Sampling" or "CanCopyFrom", for logging.
+func TextureUsageNames(usage Rendering.TextureUsageBits) []string {
+	var names []string
+	for _, bit := range textureUsageNames {
+		if usage&bit.bit != 0 {
+			names = append(names, bit.name)
+		}
+	}
+	return names
+}
+
+// TextureArray2D creates a 2D texture array (sampled in shaders as a sampler2DArray), such as for
+// the frames of a sprite sheet, with each of the layers being a separate width by height image. The
+// layers can be uploaded with [TextureArrayUpdateLayer].
+func TextureArray2D(dev Instance, width, height, layers int, format Rendering.DataFormat) RID.Texture {
+	tf := RDTextureFormat.New()
+	tf.SetTextureType(Rendering.TextureType2dArray)
+	tf.SetFormat(format)
+	tf.SetWidth(width)
+	tf.SetHeight(height)
+	tf.SetArrayLayers(layers)
+	tf.SetUsageBits(Rendering.TextureUsageSamplingBit | Rendering.TextureUsageCanUpdateBit | Rendering.TextureUsageCanCopyFromBit)
+	return dev.TextureCreate(tf, RDTextureView.New())
+}
+
+// TextureArrayUpdateLayer replaces the contents of a single layer of the texture array. The length
+// of data must match the size of a layer, when the format has a fixed texel size.
+func TextureArrayUpdateLayer(dev Instance, texture RID.Texture, layer int, data []byte) error {
+	format := dev.TextureGetFormat(texture)
+	if layer < 0 || layer >= format.ArrayLayers() {
+		return fmt.Errorf("layer %v is outside of the %v layer texture array", layer, format.ArrayLayers())
+	}
+	if texel := formatPixelSize(format.Format()); texel != 0 {
+		if expected := format.Width() * format.Height() * texel; len(data) != expected {
+			return fmt.Errorf("layer data is %v bytes, expected %v bytes", len(data), expected)
+		}
+	}
+	return dev.TextureUpdate(texture, layer, data)
+}
+
+// TextureCreateFromBytes creates a 2D texture from data holding rows of texels that are row_stride
+// bytes apart, such as a sub-image of a larger image, or an image with padded rows. The rows are
+// repacked tightly before uploading, as expected by [Instance.TextureCreate]. A row_stride of zero
+// means that the rows are already tightly packed. The format must have a fixed texel size.
+func TextureCreateFromBytes(dev Instance, size Vector2i.XY, format Rendering.DataFormat, data []byte, row_stride int) (RID.Texture, error) {
+	if size.X <= 0 || size.Y <= 0 {
+		return RID.Texture(0), fmt.Errorf("invalid texture size %v", size)
+	}
+	texel := formatPixelSize(format)
+	if texel == 0 {
+		return RID.Texture(0), fmt.Errorf("format %v does not have a fixed texel size", format)
+	}
+	width, height := int(size.X), int(size.Y)
+	row := width * texel
+	if row_stride == 0 {
+		row_stride = row
+	}
+	if row_stride < row {
+		return RID.Texture(0), fmt.Errorf("row stride of %v bytes is less than the %v bytes of a row", row_stride, row)
+	}
+	if expected := (height-1)*row_stride + row; len(data) < expected {
+		return RID.Texture(0), fmt.Errorf("texture data is %v bytes, expected at least %v bytes", len(data), expected)
+	}
+	packed := data[:row*height]
+	if row_stride != row {
+		packed = make([]byte, row*height)
+		for y := range height {
+			copy(packed[y*row:(y+1)*row], data[y*row_stride:])
+		}
+	}
+	tf := RDTextureFormat.New()
+	tf.SetTextureType(Rendering.TextureType2d)
+	tf.SetFormat(format)
+	tf.SetWidth(width)
+	tf.SetHeight(height)
+	tf.SetUsageBits(Rendering.TextureUsageSamplingBit | Rendering.TextureUsageCanUpdateBit | Rendering.TextureUsageCanCopyFromBit)
+	texture := Expanded(dev).TextureCreate(tf, RDTextureView.New(), [][]byte{packed})
+	if texture == RID.Texture(0) {
+		return texture, fmt.Errorf("failed to create %vx%v texture", width, height)
+	}
+	return texture, nil
+}
+
+// TextureCreateLike creates an empty texture with the same format as the template texture, with
+// the extra usage bits added to its usage, such as for a history buffer or a ping-pong target.
+func TextureCreateLike(dev Instance, template RID.Texture, extra_usage Rendering.TextureUsageBits) (RID.Texture, error) {
+	if !dev.TextureIsValid(template) {
+		return RID.Texture(0), fmt.Errorf("invalid template texture %v", template)
+	}
+	format := dev.TextureGetFormat(template)
+	format.SetUsageBits(format.UsageBits() | extra_usage)
+	texture := dev.TextureCreate(format, RDTextureView.New())
+	if texture == RID.Texture(0) {
+		return texture, fmt.Errorf("failed to create texture like %v", template)
+	}
+	return texture, nil
+}
+
+// TextureGetDataTyped is like [Instance.TextureGetData] but returns the texels of the layer as
+// values of type T, such as float32 for [Rendering.DataFormatR32Sfloat] or [4]uint8 for
+// [Rendering.DataFormatR8g8b8a8Unorm]. An error is returned if the size of T does not match the
+// texel size of the texture's format. T must not contain any pointers.
+//
+// Note: This function blocks the GPU until the data is retrieved.
+func TextureGetDataTyped[T any](dev Instance, texture RID.Texture, layer int) ([]T, error) {
+	format := dev.TextureGetFormat(texture).Format()
+	texel := formatPixelSize(format)
+	if texel == 0 {
+		return nil, fmt.Errorf("format %v does not have a fixed texel size", format)
+	}
+	if size := int(unsafe.Sizeof([1]T{}[0])); size != texel {
+		return nil, fmt.Errorf("%T is %d bytes, but texels of format %v are %d bytes", [1]T{}[0], size, format, texel)
+	}
+	data := dev.TextureGetData(texture, layer)
+	return decodeSlice[T](data, len(data)/texel)
+}
@@ -0,0 +1,77 @@
+package RenderingDevice
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Timestamp is a single captured timestamp, as recorded by [Instance.CaptureTimestamp].
+type Timestamp struct {
+	Name  string
+	CPU   time.Duration // since the engine started.
+	GPU   time.Duration // since the engine started.
+	Frame int           // frame that the timestamp was captured in.
+}
+
+// Timestamps returns the timestamps captured for the last frame that has timestamps
+// available for querying.
+func Timestamps(dev Instance) []Timestamp {
+	count := dev.GetCapturedTimestampsCount()
+	frame := dev.GetCapturedTimestampsFrame()
+	timestamps := make([]Timestamp, count)
+	for i := range timestamps {
+		timestamps[i] = Timestamp{
+			Name:  dev.GetCapturedTimestampName(i),
+			CPU:   time.Duration(dev.GetCapturedTimestampCpuTime(i)) * time.Microsecond,
+			GPU:   time.Duration(dev.GetCapturedTimestampGpuTime(i)) * time.Microsecond,
+			Frame: frame,
+		}
+	}
+	return timestamps
+}
+
+// WriteTimestampsCSV writes the captured [Timestamps] of the device to w as CSV rows
+// of name, cpu_us, gpu_us and frame, preceded by a header row.
+func WriteTimestampsCSV(dev Instance, w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"name", "cpu_us", "gpu_us", "frame"}); err != nil {
+		return err
+	}
+	for _, ts := range Timestamps(dev) {
+		if err := out.Write([]string{
+			ts.Name,
+			strconv.FormatInt(ts.CPU.Microseconds(), 10),
+			strconv.FormatInt(ts.GPU.Microseconds(), 10),
+			strconv.Itoa(ts.Frame),
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// TimestampWindow is a measurement window over the captured timestamps of a device. The captured
+// timestamps are replaced every frame, rather than accumulating, so they cannot be cleared, yet
+// until the first frame of the window has been captured, they still belong to an earlier frame.
+// The window discards those stale timestamps, so that measurements don't bleed across windows.
+type TimestampWindow struct {
+	dev   Instance
+	frame int
+}
+
+// NewTimestampWindow starts a new measurement window, ignoring any timestamps captured so far.
+func NewTimestampWindow(dev Instance) TimestampWindow {
+	return TimestampWindow{dev: dev, frame: dev.GetCapturedTimestampsFrame()}
+}
+
+// Timestamps returns the most recently captured [Timestamps], or nil if none have been captured
+// since the window started.
+func (w TimestampWindow) Timestamps() []Timestamp {
+	if w.dev.GetCapturedTimestampsFrame() <= w.frame {
+		return nil
+	}
+	return Timestamps(w.dev)
+}
@@ -0,0 +1,91 @@
+package RenderingDevice
+
+import (
+	"fmt"
+	"reflect"
+
+	"graphics.gd/variant/RID"
+)
+
+// UniformRing is a ring of uniform buffers of the same size, one for each frame in flight, so that
+// per-frame uniforms can be written to without stalling the GPU while it reads the previous frame's
+// uniforms.
+type UniformRing struct {
+	dev     Instance
+	buffers []RID.UniformBuffer
+}
+
+// NewUniformRing creates a ring of uniform buffers with size bytes each, one for each of the given
+// number of frames, which should be at least [Instance.GetFrameDelay] + 1. If frames is zero,
+// [Instance.GetFrameDelay] + 1 buffers are created. If any of the buffers cannot be created, those
+// already created are freed and an error is returned.
+func NewUniformRing(dev Instance, size, frames int) (*UniformRing, error) {
+	if frames <= 0 {
+		frames = dev.GetFrameDelay() + 1
+	}
+	ring := &UniformRing{dev: dev, buffers: make([]RID.UniformBuffer, 0, frames)}
+	for range frames {
+		buffer := dev.UniformBufferCreate(size)
+		if buffer == RID.UniformBuffer(0) {
+			ring.Free()
+			return nil, fmt.Errorf("failed to create uniform buffer of %d bytes", size)
+		}
+		stateOf(dev).sized(RID.Any(buffer), size)
+		ring.buffers = append(ring.buffers, buffer)
+	}
+	return ring, nil
+}
+
+// Next returns the uniform buffer for the frame that the device is currently recording, as per
+// [Instance.CurrentFrame], cycling through the ring from frame to frame.
+func (ring *UniformRing) Next() RID.UniformBuffer {
+	return ring.buffers[int(ring.dev.CurrentFrame()%int64(len(ring.buffers)))]
+}
+
+// Free frees each of the uniform buffers in the ring.
+func (ring *UniformRing) Free() {
+	for _, buffer := range ring.buffers {
+		ring.dev.FreeRidTracked(RID.Any(buffer))
+	}
+	ring.buffers = nil
+}
+
+// StructUniformRing is a [UniformRing] holding values of type T, packed as per the std140 layout
+// rules of GLSL uniform blocks.
+type StructUniformRing[T any] struct {
+	ring *UniformRing
+	data []byte
+}
+
+// NewUniformRingForStruct creates a [StructUniformRing] with each buffer sized to hold T, see
+// [NewUniformRing]. The fields of T may be bool, int32, uint32, float32 and float64 values, arrays
+// of two to four 32-bit values (which are packed as vectors), nested structs or arrays thereof. An
+// error is returned if T contains any other types.
+func NewUniformRingForStruct[T any](dev Instance, frames int) (*StructUniformRing[T], error) {
+	size, _, err := std140(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	ring, err := NewUniformRing(dev, size, frames)
+	if err != nil {
+		return nil, err
+	}
+	return &StructUniformRing[T]{ring: ring, data: make([]byte, size)}, nil
+}
+
+// WriteNext packs the value into the uniform buffer for the frame that the device is currently
+// recording (see [UniformRing.Next]) and returns the buffer, ready to bind.
+func (ring *StructUniformRing[T]) WriteNext(value T) (RID.UniformBuffer, error) {
+	clear(ring.data)
+	packStd140(ring.data, reflect.ValueOf(value))
+	buffer := ring.ring.Next()
+	if err := ring.ring.dev.BufferUpdate(RID.Buffer(buffer), 0, len(ring.data), ring.data); err != nil {
+		return buffer, err
+	}
+	return buffer, nil
+}
+
+// Free frees each of the uniform buffers in the ring.
+func (ring *StructUniformRing[T]) Free() {
+	ring.ring.Free()
+}
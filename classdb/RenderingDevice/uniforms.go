@@ -0,0 +1,40 @@
+package RenderingDevice
+
+import (
+	"fmt"
+
+	"graphics.gd/classdb/RDUniform"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+// UniformTextureBuffer returns a uniform that binds the texture buffer (a texel buffer, created with
+// [Instance.TextureBufferCreate]) as a samplerBuffer/textureBuffer to the given binding.
+func UniformTextureBuffer(binding int, buffer RID.TextureBuffer) RDUniform.Instance {
+	return uniform(Rendering.UniformTypeTextureBuffer, binding, RID.Any(buffer))
+}
+
+// UniformTextureArray returns a uniform that binds each of the textures to a single binding, for
+// use with arrays of textures in shaders, such as layout(binding = 0) uniform texture2D textures[]
+// for bindless rendering. An error is returned if there are more textures than the device allows
+// for a single shader stage.
+func UniformTextureArray(dev Instance, binding int, textures []RID.Texture) (RDUniform.Instance, error) {
+	if limit := dev.LimitGet(Rendering.LimitMaxTexturesPerShaderStage); len(textures) > limit {
+		return RDUniform.Instance{}, fmt.Errorf("%d textures exceeds the device limit of %d per shader stage", len(textures), limit)
+	}
+	ids := make([]RID.Any, len(textures))
+	for i, texture := range textures {
+		ids[i] = RID.Any(texture)
+	}
+	return uniform(Rendering.UniformTypeTexture, binding, ids...), nil
+}
+
+// UniformInputAttachment returns a uniform that binds the texture as an input attachment (a
+// subpassInput in shaders), for reading what an earlier pass of a multipass framebuffer wrote to
+// the texture at the same pixel. Input attachments only work within a multipass framebuffer, the
+// texture must be one of its attachments and be listed in the input attachments of the reading
+// pass (see [FramebufferPass]), and it must have been created with
+// [Rendering.TextureUsageInputAttachmentBit].
+func UniformInputAttachment(binding int, texture RID.Texture) RDUniform.Instance {
+	return uniform(Rendering.UniformTypeInputAttachment, binding, RID.Any(texture))
+}
@@ -0,0 +1,71 @@
+package RenderingDevice
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"graphics.gd/classdb/RDUniform"
+	"graphics.gd/classdb/Rendering"
+	"graphics.gd/variant/RID"
+)
+
+// UniformSetInfo describes a uniform set created with [Instance.UniformSetCreateTracked].
+type UniformSetInfo struct {
+	RID    RID.UniformSet
+	Shader RID.Shader // shader that the uniform set was created for.
+	Set    int        // set index within the shader.
+}
+
+// UniformSetCreateTracked is like [Instance.UniformSetCreate] but records the shader and set
+// index that the uniform set was created for, so that it is listed by [Instance.DebugListUniformSets].
+func (self Instance) UniformSetCreateTracked(uniforms []RDUniform.Instance, shader RID.Shader, shader_set int) RID.UniformSet {
+	set := self.UniformSetCreate(uniforms, shader, shader_set)
+	if set == 0 {
+		return set
+	}
+	s := stateOf(self)
+	s.track(RID.Any(set))
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.sets == nil {
+		s.sets = make(map[RID.UniformSet]UniformSetInfo)
+	}
+	s.sets[set] = UniformSetInfo{RID: set, Shader: shader, Set: shader_set}
+	return set
+}
+
+// DebugListUniformSets returns each uniform set created with [Instance.UniformSetCreateTracked]
+// that is still valid, in order of creation. Uniform sets that have since been freed, either
+// directly or because a resource they depend on was freed, are forgotten.
+func (self Instance) DebugListUniformSets() []UniformSetInfo {
+	s := stateOf(self)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var sets = make([]UniformSetInfo, 0, len(s.sets))
+	for rid, info := range s.sets {
+		if !self.UniformSetIsValid(rid) {
+			delete(s.sets, rid)
+			delete(s.tracked, RID.Any(rid))
+			continue
+		}
+		sets = append(sets, info)
+	}
+	slices.SortFunc(sets, func(a, b UniformSetInfo) int { return cmp.Compare(a.RID, b.RID) })
+	return sets
+}
+
+// UniformSetForStorageImage creates a uniform set for the shader that binds the texture as a
+// storage image (an image2D in shaders, without a sampler) at the given binding, which is how
+// compute shaders usually write their output. An error is returned if the texture was not created
+// with [Rendering.TextureUsageStorageBit].
+func UniformSetForStorageImage(dev Instance, shader RID.Shader, shader_set, binding int, texture RID.Texture) (RID.UniformSet, error) {
+	if TextureUsage(dev, texture)&Rendering.TextureUsageStorageBit == 0 {
+		return RID.UniformSet(0), fmt.Errorf("texture %v cannot be used as a storage image", texture)
+	}
+	set := dev.UniformSetCreate([]RDUniform.Instance{uniform(Rendering.UniformTypeImage, binding, RID.Any(texture))}, shader, shader_set)
+	if set == 0 {
+		return set, fmt.Errorf("failed to create uniform set %d for shader %v", shader_set, shader)
+	}
+	return set, nil
+}
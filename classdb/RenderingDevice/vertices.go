@@ -0,0 +1,175 @@
+package RenderingDevice
+
+import (
+	"fmt"
+
+	"graphics.gd/classdb/RDVertexAttribute"
+	"graphics.gd/classdb/Rendering"
+	gd "graphics.gd/internal"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/Packed"
+	"graphics.gd/variant/RID"
+)
+
+// vertexAttribute is the Go-side copy of an [RDVertexAttribute.Instance].
+type vertexAttribute struct {
+	offset    int
+	stride    int
+	format    Rendering.DataFormat
+	frequency Rendering.VertexFrequency
+}
+
+// VertexFormatCreateTracked is like [Instance.VertexFormatCreate] but records the attributes
+// of the vertex format, so that [VertexArrayCreateChecked] can validate vertex arrays against it.
+func (self Instance) VertexFormatCreateTracked(vertex_descriptions []RDVertexAttribute.Instance) int {
+	format := self.VertexFormatCreate(vertex_descriptions)
+	if format < 0 {
+		return format
+	}
+	attributes := make([]vertexAttribute, len(vertex_descriptions))
+	for i, desc := range vertex_descriptions {
+		attributes[i] = vertexAttribute{
+			offset:    desc.Offset(),
+			stride:    desc.Stride(),
+			format:    desc.Format(),
+			frequency: desc.Frequency(),
+		}
+	}
+	s := stateOf(self)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.vertex == nil {
+		s.vertex = make(map[int][]vertexAttribute)
+	}
+	s.vertex[format] = attributes
+	return format
+}
+
+// VertexBufferCreateTracked is like [Expanded.VertexBufferCreate] but records the size of the
+// buffer, so that [VertexArrayCreateChecked] can validate vertex arrays that use it.
+func (self Instance) VertexBufferCreateTracked(size_bytes int, data []byte) RID.VertexBuffer {
+	buffer := Expanded(self).VertexBufferCreate(size_bytes, data, 0)
+	if buffer == 0 {
+		return buffer
+	}
+	s := stateOf(self)
+	s.track(RID.Any(buffer))
+	s.sized(RID.Any(buffer), size_bytes)
+	return buffer
+}
+
+// VertexArrayCreateChecked is like [Expanded.VertexArrayCreate] but first validates that there is
+// one buffer (and offset, if any are given) for each attribute of the vertex format, and that each
+// buffer is large enough to hold count vertices of that attribute. The vertex format must have been
+// created with [Instance.VertexFormatCreateTracked], buffers are only validated against their size
+// when they were created with [Instance.VertexBufferCreateTracked].
+func VertexArrayCreateChecked(dev Instance, count, format int, buffers []RID.VertexBuffer, offsets []int) (RID.VertexArray, error) {
+	s := stateOf(dev)
+	s.mutex.Lock()
+	attributes, ok := s.vertex[format]
+	sizes := make([]int, len(buffers))
+	for i, buffer := range buffers {
+		sizes[i] = s.sizes[RID.Any(buffer)]
+	}
+	s.mutex.Unlock()
+	if !ok {
+		return RID.VertexArray(0), fmt.Errorf("vertex format %d was not created with VertexFormatCreateTracked", format)
+	}
+	if len(buffers) != len(attributes) {
+		return RID.VertexArray(0), fmt.Errorf("vertex format %d has %d attributes, but %d buffers were given", format, len(attributes), len(buffers))
+	}
+	if len(offsets) != 0 && len(offsets) != len(buffers) {
+		return RID.VertexArray(0), fmt.Errorf("%d offsets were given for %d buffers", len(offsets), len(buffers))
+	}
+	for i, attribute := range attributes {
+		if sizes[i] == 0 {
+			continue
+		}
+		vertices := count
+		if attribute.frequency == Rendering.VertexFrequencyInstance {
+			vertices = 1
+		}
+		need := attribute.offset + (vertices-1)*attribute.stride + formatPixelSize(attribute.format)
+		if len(offsets) != 0 {
+			need += offsets[i]
+		}
+		if have := sizes[i]; need > have {
+			return RID.VertexArray(0), fmt.Errorf("buffer %d too small: need %d bytes, have %d", i, need, have)
+		}
+	}
+	var packed = make([]int64, len(offsets))
+	for i, offset := range offsets {
+		packed[i] = int64(offset)
+	}
+	array := RID.VertexArray(Advanced(dev).VertexArrayCreate(int64(count), int64(format), gd.ArrayFromSlice[Array.Contains[RID.Any]](buffers), Packed.New(packed...)))
+	if array == 0 {
+		return array, fmt.Errorf("failed to create vertex array of %d vertices", count)
+	}
+	return array, nil
+}
+
+// AttrSpec specifies a per-vertex attribute for [InterleavedVertexFormat] and [SeparateVertexFormat].
+type AttrSpec struct {
+	Location int                  // shader location of the attribute.
+	Format   Rendering.DataFormat // must have a fixed texel size.
+}
+
+// InterleavedVertexFormat returns a vertex format where the attributes are interleaved within a
+// single buffer, one after another in the given order, with the offsets and the shared stride
+// computed from the size of each attribute's format. Formats are cached on the device, so each
+// distinct layout is only created once. Returns -1 if any of the formats lack a fixed size.
+func InterleavedVertexFormat(dev Instance, attrs []AttrSpec) int {
+	return vertexLayout(dev, "interleaved", attrs, true)
+}
+
+// SeparateVertexFormat returns a vertex format where each attribute is read from its own tightly
+// packed buffer, see [InterleavedVertexFormat].
+func SeparateVertexFormat(dev Instance, attrs []AttrSpec) int {
+	return vertexLayout(dev, "separate", attrs, false)
+}
+
+func vertexLayout(dev Instance, kind string, attrs []AttrSpec, interleaved bool) int {
+	key := fmt.Sprint(kind, attrs)
+	s := stateOf(dev)
+	s.mutex.Lock()
+	format, ok := s.layouts[key]
+	s.mutex.Unlock()
+	if ok {
+		return format
+	}
+	var stride int
+	descriptions := make([]RDVertexAttribute.Instance, len(attrs))
+	for i, attr := range attrs {
+		size := formatPixelSize(attr.Format)
+		if size == 0 {
+			return -1
+		}
+		desc := RDVertexAttribute.New()
+		desc.SetLocation(attr.Location)
+		desc.SetFormat(attr.Format)
+		desc.SetFrequency(Rendering.VertexFrequencyVertex)
+		if interleaved {
+			desc.SetOffset(stride)
+		} else {
+			desc.SetStride(size)
+		}
+		stride += size
+		descriptions[i] = desc
+	}
+	if interleaved {
+		for _, desc := range descriptions {
+			desc.SetStride(stride)
+		}
+	}
+	format = dev.VertexFormatCreateTracked(descriptions)
+	if format < 0 {
+		return format
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.layouts == nil {
+		s.layouts = make(map[string]int)
+	}
+	s.layouts[key] = format
+	return format
+}
@@ -93,6 +93,9 @@ func (classDB ClassDB) simpleCall(w io.Writer, class gdjson.Class, method gdjson
 	if method.IsStatic {
 		fmt.Fprintf(w, "self := Instance{}\n")
 	}
+	if class.Name == "RenderingDevice" && method.Name == "free_rid" {
+		fmt.Fprintf(w, "if !self.freeing(rid) {\n\treturn\n}\n\t") // see classdb/RenderingDevice/frees.go
+	}
 	if method.IsVararg {
 		fmt.Fprint(w, "var converted_variants = make([]gd.Variant, len(args))\n")
 		fmt.Fprint(w, "for i, arg := range args {\n")
@@ -17,3 +17,19 @@ func BenchmarkPacked(b *testing.B) {
 	}
 	fmt.Println(sum)
 }
+
+func TestBytesFrom(t *testing.T) {
+	data := []byte{1, 2, 3}
+	packed := Packed.BytesFrom(data)
+	if view := packed.Bytes(); &view[0] != &data[0] {
+		t.Fatal("expected Bytes to return the slice given to BytesFrom, without copying it")
+	}
+	data[1] = 8
+	if packed.Index(1) != 8 {
+		t.Fatalf("expected changes to the slice to be visible in the array, got %v", packed.Index(1))
+	}
+	packed.SetIndex(0, 9)
+	if data[0] != 9 {
+		t.Fatalf("expected changes to the array to be visible in the slice, got %v", data)
+	}
+}
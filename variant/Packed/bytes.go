@@ -27,6 +27,11 @@ const (
 // Bytes provides additional methods for working with arrays of bytes.
 type Bytes Array[byte]
 
+// BytesFrom returns an array that refers to the given slice, without copying it. The slice is only
+// copied once the array is passed to the engine (which always copies the data into its own memory),
+// so b must stay unmodified until then, and changes made through the array are visible in b.
+func BytesFrom(b []byte) Bytes { return Bytes(New(b...)) }
+
 // Index returns the element at the given index.
 func (array Bytes) Index(idx int) byte { return (GenericArray.Contains[byte])(array).Index(idx) } //gd:PackedByteArray.get
 
@@ -124,7 +129,9 @@ func (array Bytes) BinarySearch(value byte, before bool) int { //gd:PackedByteAr
 	return GenericArray.BinarySearch((GenericArray.Contains[byte])(array), value, before)
 }
 
-// Bytes returns the underlying data in the array as a slice of bytes.
+// Bytes returns the underlying data in the array as a slice of bytes. Unless the array is backed
+// by engine memory (in which case a copy is returned), the slice aliases the array without copying
+// it, so it must not be modified, nor used after the array is modified.
 func (array Bytes) Bytes() []byte {
 	return (GenericArray.Contains[byte])(array).Slice()
 }
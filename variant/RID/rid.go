@@ -9,6 +9,23 @@ func (rid Any) Int64() int64  { return *(*int64)(unsafe.Pointer(&rid)) } //gd:RI
 
 type Either[A, B ~uint64] Any
 
+// Any returns the resource as an untyped [Any], for functions that accept any kind of resource.
+func (rid Texture) Any() Any         { return Any(rid) }
+func (rid Framebuffer) Any() Any     { return Any(rid) }
+func (rid Sampler) Any() Any         { return Any(rid) }
+func (rid VertexBuffer) Any() Any    { return Any(rid) }
+func (rid VertexArray) Any() Any     { return Any(rid) }
+func (rid IndexBuffer) Any() Any     { return Any(rid) }
+func (rid IndexArray) Any() Any      { return Any(rid) }
+func (rid Shader) Any() Any          { return Any(rid) }
+func (rid StorageBuffer) Any() Any   { return Any(rid) }
+func (rid TextureBuffer) Any() Any   { return Any(rid) }
+func (rid UniformBuffer) Any() Any   { return Any(rid) }
+func (rid UniformSet) Any() Any      { return Any(rid) }
+func (rid Buffer) Any() Any          { return Any(rid) }
+func (rid RenderPipeline) Any() Any  { return Any(rid) }
+func (rid ComputePipeline) Any() Any { return Any(rid) }
+
 type (
 	ActionSet Any
 	Action    Any
@@ -9,6 +9,7 @@ import (
 	"graphics.gd/variant/Float"
 	"graphics.gd/variant/Int"
 	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
 )
 
 // XYZ is a 3-element structure that can be used to represent 3D coordinates
@@ -63,6 +64,12 @@ func New[X Int.Any | Float.Any](x, y, z X) XYZ { //gd:Vector3(x:float,y:float,z:
 	return XYZ{Float.X(x), Float.X(y), Float.X(z)}
 }
 
+// FromVector2i returns a [XYZ] with the X and Y components of the given vector and the given Z
+// component, such as for building the region arguments of texture copies from 2D texel positions.
+func FromVector2i[X Int.Any | Float.Any](v Vector2i.XY, z X) XYZ {
+	return XYZ{Float.X(v.X), Float.X(v.Y), Float.X(z)}
+}
+
 // Less compares two Vector3 vectors by first checking if the X value of the left vector is less than the X value of
 // the right vector. If the X values are exactly equal, then it repeats this check with the Y and Z values of the two vectors.
 // This operator is useful for sorting vectors.
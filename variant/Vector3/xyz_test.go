@@ -0,0 +1,22 @@
+package Vector3_test
+
+import (
+	"testing"
+
+	"graphics.gd/internal/gdtests"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+)
+
+func TestFromVector2i(t *testing.T) {
+	var v = Vector3.FromVector2i(Vector2i.New(4, 8), 1) // v is Vector3(4, 8, 1)
+	gdtests.That(t, v, Vector3.New(4, 8, 1))
+}
+
+func TestClampRegion(t *testing.T) {
+	var size = Vector3.FromVector2i(Vector2i.New(64, 32), 1)
+	var region = Vector3.Clamp(Vector3.New(-8, 16, 4), Vector3.Zero, size) // region is Vector3(0, 16, 1)
+	gdtests.That(t, region, Vector3.New(0, 16, 1))
+	gdtests.That(t, Vector3.Min(Vector3.New(80, 8, 1), size), Vector3.New(64, 8, 1))
+	gdtests.That(t, Vector3.Max(Vector3.New(-1, 40, 0), Vector3.Zero), Vector3.New(0, 40, 0))
+}